@@ -0,0 +1,195 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AlertmanagerMessage is the Prometheus Alertmanager webhook payload (version 4).
+// See https://prometheus.io/docs/alerting/latest/configuration/#webhook_config.
+type AlertmanagerMessage struct {
+	Version           string              `json:"version"`
+	GroupKey          string              `json:"groupKey"`
+	TruncatedAlerts   int                 `json:"truncatedAlerts"`
+	Status            string              `json:"status"`
+	Receiver          string              `json:"receiver"`
+	GroupLabels       map[string]string   `json:"groupLabels"`
+	CommonLabels      map[string]string   `json:"commonLabels"`
+	CommonAnnotations map[string]string   `json:"commonAnnotations"`
+	ExternalURL       string              `json:"externalURL"`
+	Alerts            []AlertmanagerAlert `json:"alerts"`
+}
+
+// AlertmanagerAlert is a single alert of the Alertmanager webhook payload.
+type AlertmanagerAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+const (
+	alertmanagerVersion = "4"
+
+	alertmanagerStatusFiring   = "firing"
+	alertmanagerStatusResolved = "resolved"
+
+	// maxAlertmanagerPayload limits the size of the webhook request body.
+	maxAlertmanagerPayload = 1 << 20
+)
+
+// alertmanagerHandler forwards Alertmanager webhook alerts to a Notifier.
+type alertmanagerHandler struct {
+	notifier Notifier
+}
+
+// NewAlertmanagerHandler returns a new http.Handler that accepts Prometheus Alertmanager webhook
+// payloads (version 4) and forwards every alert to the notifier.
+//
+// The `severity` label is parsed with ParseSeverity (e.g. debug, info, warning, error, critical); unknown or missing values
+// are treated as SeverityWarning, resolved alerts are always sent as SeverityInfo.
+// Labels and annotations are added to the Metadata extra fields, the `summary` annotation (or
+// `description`, or the `alertname` label) is used as the alert message.
+func NewAlertmanagerHandler(n Notifier) (http.Handler, error) {
+	if n == nil {
+		return nil, fmt.Errorf("notifier is nil")
+	}
+
+	return &alertmanagerHandler{
+		notifier: n,
+	}, nil
+}
+
+// ServeHTTP handles the Alertmanager webhook request.
+func (h *alertmanagerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	var msg AlertmanagerMessage
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAlertmanagerPayload)).Decode(&msg); err != nil {
+		http.Error(w, fmt.Sprintf("decode payload: %v", err), http.StatusBadRequest)
+
+		return
+	}
+
+	if msg.Version != "" && msg.Version != alertmanagerVersion {
+		http.Error(w, fmt.Sprintf("unsupported payload version %q", msg.Version), http.StatusBadRequest)
+
+		return
+	}
+
+	if err := h.forward(r.Context(), msg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// forward sends all alerts of the message to the notifier.
+func (h *alertmanagerHandler) forward(ctx context.Context, msg AlertmanagerMessage) error {
+	var errs []error
+
+	for i := range msg.Alerts {
+		alert := msg.Alerts[i]
+
+		severity, message := alertmanagerAlert(alert)
+
+		actx := ContextWithMetadata(ctx, alertmanagerMetadata(msg, alert))
+
+		if err := h.notifier.Alert(actx, severity, message); err != nil {
+			errs = append(errs, fmt.Errorf("forward alert '%s': %w", alert.Labels["alertname"], err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// alertmanagerAlert returns the severity and the message of the alert.
+func alertmanagerAlert(alert AlertmanagerAlert) (Severity, string) {
+	severity, err := ParseSeverity(alert.Labels["severity"])
+	if err != nil {
+		severity = SeverityWarning
+	}
+
+	status := alert.Status
+	if status == "" {
+		status = alertmanagerStatusFiring
+	}
+
+	if status == alertmanagerStatusResolved {
+		severity = SeverityInfo
+	}
+
+	text := alert.Annotations["summary"]
+	if text == "" {
+		text = alert.Annotations["description"]
+	}
+
+	if text == "" {
+		text = alert.Labels["alertname"]
+	}
+
+	message := "[" + strings.ToUpper(status) + "]"
+
+	if name := alert.Labels["alertname"]; name != "" && name != text {
+		message += " " + name + ":"
+	}
+
+	if text != "" {
+		message += " " + text
+	}
+
+	return severity, message
+}
+
+// alertmanagerMetadata builds the alert metadata from the alert labels and annotations.
+func alertmanagerMetadata(msg AlertmanagerMessage, alert AlertmanagerAlert) Metadata {
+	extra := make(map[string]string, len(alert.Labels)+len(alert.Annotations))
+
+	for k, v := range alert.Labels {
+		extra[k] = v
+	}
+
+	for k, v := range alert.Annotations {
+		extra[k] = v
+	}
+
+	if alert.Status != "" {
+		extra["status"] = alert.Status
+	}
+
+	if !alert.StartsAt.IsZero() {
+		extra["starts_at"] = alert.StartsAt.UTC().Format(time.DateTime)
+	}
+
+	if alert.Status == alertmanagerStatusResolved && !alert.EndsAt.IsZero() {
+		extra["ends_at"] = alert.EndsAt.UTC().Format(time.DateTime)
+	}
+
+	if alert.GeneratorURL != "" {
+		extra["generator_url"] = alert.GeneratorURL
+	}
+
+	if msg.Receiver != "" {
+		extra["receiver"] = msg.Receiver
+	}
+
+	return Metadata{
+		Extra: extra,
+	}
+}
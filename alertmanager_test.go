@@ -0,0 +1,150 @@
+package notifier_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/notifier"
+)
+
+const alertmanagerPayload = `{
+  "version": "4",
+  "groupKey": "{}:{alertname=\"HighLatency\"}",
+  "status": "firing",
+  "receiver": "telegram",
+  "alerts": [
+    {
+      "status": "firing",
+      "labels": {"alertname": "HighLatency", "severity": "critical", "service": "api"},
+      "annotations": {"summary": "p99 latency is above 1s"},
+      "startsAt": "2020-01-01T00:00:00Z",
+      "generatorURL": "http://prometheus/graph"
+    },
+    {
+      "status": "resolved",
+      "labels": {"alertname": "DiskFull", "severity": "warning"},
+      "annotations": {"description": "disk usage is back to normal"},
+      "startsAt": "2020-01-01T00:00:00Z",
+      "endsAt": "2020-01-01T01:00:00Z"
+    },
+    {
+      "status": "firing",
+      "labels": {"alertname": "JobFailed", "severity": "error"},
+      "annotations": {"summary": "backup job failed"},
+      "startsAt": "2020-01-01T00:00:00Z"
+    },
+    {
+      "status": "firing",
+      "labels": {"alertname": "Unknown", "severity": "page"},
+      "annotations": {"summary": "unknown severity"},
+      "startsAt": "2020-01-01T00:00:00Z"
+    }
+  ]
+}`
+
+type notifierFunc func(ctx context.Context, severity notifier.Severity, message string) error
+
+func (f notifierFunc) Alert(ctx context.Context, severity notifier.Severity, message string) error {
+	return f(ctx, severity, message)
+}
+
+func (f notifierFunc) Kind() string {
+	return "func"
+}
+
+func TestAlertmanagerHandler(t *testing.T) {
+	var buf bytes.Buffer
+
+	h, err := notifier.NewAlertmanagerHandler(newTestNotifier(t, &buf, "alertmanager"))
+	require.NoError(t, err)
+
+	ctx := notifier.ContextWithMetadata(context.Background(), notifier.Metadata{AppName: "test_app"})
+
+	req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/alerts", strings.NewReader(alertmanagerPayload))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	got := buf.String()
+
+	for _, want := range []string{
+		"CRITICAL\nAlert Message: [FIRING] HighLatency: p99 latency is above 1s",
+		"• app_name: test_app",
+		"• service: api",
+		"• starts_at: 2020-01-01 00:00:00",
+		"• generator_url: http://prometheus/graph",
+		"INFO\nAlert Message: [RESOLVED] DiskFull: disk usage is back to normal",
+		"• ends_at: 2020-01-01 01:00:00",
+		"• receiver: telegram",
+		"ERROR\nAlert Message: [FIRING] JobFailed: backup job failed",
+		"WARNING\nAlert Message: [FIRING] Unknown: unknown severity",
+	} {
+		assert.Contains(t, got, want)
+	}
+}
+
+func TestAlertmanagerHandler_Errors(t *testing.T) {
+	failing := notifierFunc(func(context.Context, notifier.Severity, string) error {
+		return errors.New("unavailable")
+	})
+
+	tests := []struct {
+		name     string
+		n        notifier.Notifier
+		method   string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "method not allowed",
+			n:        failing,
+			method:   http.MethodGet,
+			body:     "",
+			wantCode: http.StatusMethodNotAllowed,
+		},
+		{
+			name:     "invalid payload",
+			n:        failing,
+			method:   http.MethodPost,
+			body:     "{",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "unsupported version",
+			n:        failing,
+			method:   http.MethodPost,
+			body:     `{"version": "3"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "notifier failed",
+			n:        failing,
+			method:   http.MethodPost,
+			body:     alertmanagerPayload,
+			wantCode: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := notifier.NewAlertmanagerHandler(tt.n)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, "/alerts", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of the circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed passes alerts to the notifier.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects alerts without calling the notifier.
+	CircuitOpen
+	// CircuitHalfOpen passes a single trial alert to check if the notifier recovered.
+	CircuitHalfOpen
+)
+
+// String returns the state name.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitBreakerOptions are options for the notifier created by NewCircuitBreaker.
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit. If zero, 5 is used.
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before a trial alert is let through. If zero, 30s is used.
+	OpenTimeout time.Duration
+	// Fallback receives the alerts rejected by the open circuit and the alerts the notifier failed to deliver.
+	// If nil, ErrCircuitOpen is returned while the circuit is open.
+	Fallback Notifier
+	// OnStateChange is called on every state transition. It must not block.
+	OnStateChange func(from, to CircuitState)
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+}
+
+const (
+	defaultCircuitFailureThreshold = 5
+	defaultCircuitOpenTimeout      = 30 * time.Second
+)
+
+// CircuitBreaker is a notifier that stops calling a failing notifier for a while.
+type CircuitBreaker struct {
+	next Notifier
+	opts CircuitBreakerOptions
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	// openFor is how long the circuit stays open, OpenTimeout or the longer RetryAfter of the failure.
+	openFor time.Duration
+	// probing is set while the half-open trial alert is in flight.
+	probing bool
+}
+
+// NewCircuitBreaker returns a new notifier that opens the circuit after FailureThreshold consecutive
+// retryable failures of the next notifier (see IsRetryable), or at once when the notifier fails
+// with the RetryAfter delay (see DeliveryError).
+// While the circuit is open, alerts fail fast with ErrCircuitOpen or go to the Fallback. After OpenTimeout,
+// or the longer RetryAfter, a single trial alert is passed to the next notifier:
+// the circuit closes if it succeeds and opens again if it fails.
+func NewCircuitBreaker(next Notifier, opts *CircuitBreakerOptions) (*CircuitBreaker, error) {
+	if next == nil {
+		return nil, fmt.Errorf("notifier is nil")
+	}
+
+	b := &CircuitBreaker{
+		next: next,
+	}
+
+	if opts != nil {
+		b.opts = *opts
+	}
+
+	if b.opts.FailureThreshold <= 0 {
+		b.opts.FailureThreshold = defaultCircuitFailureThreshold
+	}
+
+	if b.opts.OpenTimeout <= 0 {
+		b.opts.OpenTimeout = defaultCircuitOpenTimeout
+	}
+
+	if b.opts.Now == nil {
+		b.opts.Now = time.Now
+	}
+
+	return b, nil
+}
+
+// Kind returns the notifier kind.
+func (b *CircuitBreaker) Kind() string {
+	return b.next.Kind()
+}
+
+// State returns the current circuit state.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// Alert sends the alert to the next notifier unless the circuit is open.
+func (b *CircuitBreaker) Alert(ctx context.Context, severity Severity, message string) error {
+	if err := validateAlert(severity, message); err != nil {
+		return err
+	}
+
+	if !b.allow() {
+		return b.fallback(ctx, severity, message, fmt.Errorf("send alert to '%s': %w", b.next.Kind(), ErrCircuitOpen))
+	}
+
+	err := b.call(ctx, severity, message)
+	if err != nil {
+		return b.fallback(ctx, severity, message, err)
+	}
+
+	return nil
+}
+
+// call sends the alert to the next notifier and records the result. A panic of the notifier is
+// recorded as a failure, so the half-open probe is not left in flight, and is propagated.
+func (b *CircuitBreaker) call(ctx context.Context, severity Severity, message string) error {
+	recorded := false
+
+	defer func() {
+		if !recorded {
+			b.record(ctx, fmt.Errorf("notifier '%s' panicked", b.next.Kind()))
+		}
+	}()
+
+	err := b.next.Alert(ctx, severity, message)
+
+	recorded = true
+
+	b.record(ctx, err)
+
+	return err
+}
+
+// fallback sends the alert to the fallback notifier, if any, or returns err.
+func (b *CircuitBreaker) fallback(ctx context.Context, severity Severity, message string, err error) error {
+	if b.opts.Fallback == nil {
+		return err
+	}
+
+	if ferr := b.opts.Fallback.Alert(ctx, severity, message); ferr != nil {
+		return fmt.Errorf("%w; fallback '%s': %w", err, b.opts.Fallback.Kind(), ferr)
+	}
+
+	return nil
+}
+
+// allow checks if the alert could be passed to the next notifier.
+func (b *CircuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if b.opts.Now().Sub(b.openedAt) < b.openFor {
+			return false
+		}
+
+		b.setState(CircuitHalfOpen)
+		b.probing = true
+
+		return true
+	case CircuitHalfOpen:
+		if b.probing {
+			return false
+		}
+
+		b.probing = true
+
+		return true
+	default:
+		return true
+	}
+}
+
+// record updates the circuit with the delivery result of the alert sent with ctx.
+func (b *CircuitBreaker) record(ctx context.Context, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitHalfOpen {
+		b.probing = false
+	}
+
+	if err == nil {
+		b.failures = 0
+
+		if b.state != CircuitClosed {
+			b.setState(CircuitClosed)
+		}
+
+		return
+	}
+
+	// Errors of the alert itself, e.g. a too long message, and the cancelled context of the caller
+	// say nothing about the notifier health.
+	if !IsRetryable(err) || ctx.Err() != nil {
+		return
+	}
+
+	b.failures++
+
+	// The destination asking to retry later, e.g. on rate limiting, opens the circuit immediately.
+	retryAfter := RetryAfter(err)
+
+	if b.state == CircuitHalfOpen ||
+		(b.state == CircuitClosed && (b.failures >= b.opts.FailureThreshold || retryAfter > 0)) {
+		b.openedAt = b.opts.Now()
+		b.openFor = max(b.opts.OpenTimeout, retryAfter)
+		b.setState(CircuitOpen)
+	}
+}
+
+// setState changes the state and reports the transition. Must be called with mu held.
+func (b *CircuitBreaker) setState(to CircuitState) {
+	from := b.state
+	b.state = to
+
+	if b.opts.OnStateChange != nil {
+		b.opts.OnStateChange(from, to)
+	}
+}
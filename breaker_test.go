@@ -0,0 +1,226 @@
+package notifier_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/notifier"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var (
+		up    bool
+		calls int
+	)
+
+	telegram := notifierFunc(func(context.Context, notifier.Severity, string) error {
+		calls++
+
+		if !up {
+			return errors.New("telegram is down")
+		}
+
+		return nil
+	})
+
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	var transitions []string
+
+	b, err := notifier.NewCircuitBreaker(telegram, &notifier.CircuitBreakerOptions{
+		FailureThreshold: 2,
+		OpenTimeout:      time.Minute,
+		OnStateChange: func(from, to notifier.CircuitState) {
+			transitions = append(transitions, from.String()+" -> "+to.String())
+		},
+		Now: func() time.Time { return now },
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	for range 2 {
+		require.EqualError(t, b.Alert(ctx, notifier.SeverityCritical, "message"), "telegram is down")
+	}
+
+	assert.Equal(t, notifier.CircuitOpen, b.State())
+
+	// Fails fast while open.
+	require.ErrorIs(t, b.Alert(ctx, notifier.SeverityCritical, "message"), notifier.ErrCircuitOpen)
+	assert.Equal(t, 2, calls)
+
+	// The trial alert fails and opens the circuit again.
+	now = now.Add(time.Minute)
+
+	require.Error(t, b.Alert(ctx, notifier.SeverityCritical, "message"))
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, notifier.CircuitOpen, b.State())
+
+	// The trial alert succeeds and closes the circuit.
+	now = now.Add(time.Minute)
+	up = true
+
+	require.NoError(t, b.Alert(ctx, notifier.SeverityCritical, "message"))
+	assert.Equal(t, notifier.CircuitClosed, b.State())
+
+	assert.Equal(t, []string{
+		"closed -> open",
+		"open -> half-open",
+		"half-open -> open",
+		"open -> half-open",
+		"half-open -> closed",
+	}, transitions)
+
+	// Invalid alerts do not count as failures.
+	require.ErrorIs(t, b.Alert(ctx, notifier.SeverityCritical, ""), notifier.ErrEmptyMessage)
+	assert.Equal(t, notifier.CircuitClosed, b.State())
+}
+
+func TestCircuitBreaker_Fallback(t *testing.T) {
+	down := notifierFunc(func(context.Context, notifier.Severity, string) error {
+		return errors.New("telegram is down")
+	})
+
+	var email bytes.Buffer
+
+	b, err := notifier.NewCircuitBreaker(down, &notifier.CircuitBreakerOptions{
+		FailureThreshold: 1,
+		Fallback:         newTestNotifier(t, &email, "email"),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, b.Alert(context.Background(), notifier.SeverityCritical, "first"))
+	assert.Equal(t, notifier.CircuitOpen, b.State())
+
+	require.NoError(t, b.Alert(context.Background(), notifier.SeverityCritical, "second"))
+
+	assert.Contains(t, email.String(), "first")
+	assert.Contains(t, email.String(), "second")
+}
+
+func TestCircuitBreaker_ProbePanic(t *testing.T) {
+	panics := true
+
+	telegram := notifierFunc(func(context.Context, notifier.Severity, string) error {
+		if panics {
+			panic("telegram client panic")
+		}
+
+		return nil
+	})
+
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	b, err := notifier.NewCircuitBreaker(telegram, &notifier.CircuitBreakerOptions{
+		FailureThreshold: 1,
+		OpenTimeout:      time.Minute,
+		Now:              func() time.Time { return now },
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	assert.Panics(t, func() { _ = b.Alert(ctx, notifier.SeverityCritical, "first") })
+	assert.Equal(t, notifier.CircuitOpen, b.State())
+
+	// The panicked probe opens the circuit again.
+	now = now.Add(time.Minute)
+
+	assert.Panics(t, func() { _ = b.Alert(ctx, notifier.SeverityCritical, "probe") })
+	assert.Equal(t, notifier.CircuitOpen, b.State())
+
+	// The next probe is let through and closes the circuit.
+	panics = false
+	now = now.Add(time.Minute)
+
+	require.NoError(t, b.Alert(ctx, notifier.SeverityCritical, "recovered"))
+	assert.Equal(t, notifier.CircuitClosed, b.State())
+}
+
+func TestCircuitBreaker_RetryAfter(t *testing.T) {
+	limited := true
+
+	telegram := notifierFunc(func(context.Context, notifier.Severity, string) error {
+		if limited {
+			return &notifier.DeliveryError{
+				Kind:       "telegram",
+				StatusCode: http.StatusTooManyRequests,
+				Retryable:  true,
+				RetryAfter: 5 * time.Minute,
+			}
+		}
+
+		return nil
+	})
+
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	b, err := notifier.NewCircuitBreaker(telegram, &notifier.CircuitBreakerOptions{
+		FailureThreshold: 3,
+		OpenTimeout:      time.Minute,
+		Now:              func() time.Time { return now },
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// The rate limited alert opens the circuit at once.
+	require.ErrorIs(t, b.Alert(ctx, notifier.SeverityCritical, "first"), notifier.ErrRateLimited)
+	assert.Equal(t, notifier.CircuitOpen, b.State())
+
+	limited = false
+
+	// The circuit stays open for RetryAfter, longer than OpenTimeout.
+	now = now.Add(2 * time.Minute)
+
+	require.ErrorIs(t, b.Alert(ctx, notifier.SeverityCritical, "second"), notifier.ErrCircuitOpen)
+
+	now = now.Add(3 * time.Minute)
+
+	require.NoError(t, b.Alert(ctx, notifier.SeverityCritical, "third"))
+	assert.Equal(t, notifier.CircuitClosed, b.State())
+}
+
+func TestCircuitBreaker_NotRetryable(t *testing.T) {
+	telegram := notifierFunc(func(context.Context, notifier.Severity, string) error {
+		return &notifier.DeliveryError{
+			Kind:       "telegram",
+			StatusCode: http.StatusRequestEntityTooLarge,
+		}
+	})
+
+	b, err := notifier.NewCircuitBreaker(telegram, &notifier.CircuitBreakerOptions{
+		FailureThreshold: 2,
+	})
+	require.NoError(t, err)
+
+	for range 5 {
+		require.ErrorIs(t, b.Alert(context.Background(), notifier.SeverityCritical, "message"), notifier.ErrPayloadTooLarge)
+	}
+
+	assert.Equal(t, notifier.CircuitClosed, b.State())
+}
+
+func TestCircuitBreaker_CallerCancelled(t *testing.T) {
+	telegram := notifierFunc(func(ctx context.Context, _ notifier.Severity, _ string) error {
+		return ctx.Err()
+	})
+
+	b, err := notifier.NewCircuitBreaker(telegram, &notifier.CircuitBreakerOptions{
+		FailureThreshold: 1,
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.ErrorIs(t, b.Alert(ctx, notifier.SeverityCritical, "message"), context.Canceled)
+	assert.Equal(t, notifier.CircuitClosed, b.State())
+}
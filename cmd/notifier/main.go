@@ -0,0 +1,279 @@
+// Command notifier sends an alert from scripts, cron jobs and CI pipelines.
+//
+// Usage:
+//
+//	notifier [flags] [message]
+//
+// The message is taken from the arguments, or from stdin if no arguments are passed or the message is "-".
+// Notifiers are configured by flags or environment variables:
+//
+//	NOTIFIER_URLS               whitespace separated list of notifier URLs, see notifier.FromURL
+//	NOTIFIER_TELEGRAM_TOKEN     telegram bot token
+//	NOTIFIER_TELEGRAM_CHAT_IDS  comma separated list of telegram chat ids
+//	NOTIFIER_STDOUT             print alert to stdout (true/false)
+//	NOTIFIER_SEVERITY           alert severity (DEBUG, INFO, WARNING, ERROR, CRITICAL, EMERGENCY)
+//	NOTIFIER_APP_NAME           application name added to the alert metadata
+//	NOTIFIER_INSTANCE_NAME      instance name added to the alert metadata
+//
+// The exit code is 0 on success, 1 if the alert was not delivered and 2 on invalid usage.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/obalunenko/getenv"
+
+	"github.com/obalunenko/notifier"
+)
+
+const (
+	exitOK    = 0
+	exitFail  = 1
+	exitUsage = 2
+)
+
+const (
+	envURLs            = "NOTIFIER_URLS"
+	envTelegramToken   = "NOTIFIER_TELEGRAM_TOKEN"
+	envTelegramChatIDs = "NOTIFIER_TELEGRAM_CHAT_IDS"
+	envStdout          = "NOTIFIER_STDOUT"
+	envSeverity        = "NOTIFIER_SEVERITY"
+	envAppName         = "NOTIFIER_APP_NAME"
+	envInstanceName    = "NOTIFIER_INSTANCE_NAME"
+	envTimeout         = "NOTIFIER_TIMEOUT"
+)
+
+const defaultTimeout = 30 * time.Second
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+
+	stop()
+
+	os.Exit(code)
+}
+
+// metaFlag collects repeatable key=value flags.
+type metaFlag map[string]string
+
+func (m metaFlag) String() string {
+	pairs := make([]string, 0, len(m))
+
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+func (m metaFlag) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("invalid meta %q, should be key=value", s)
+	}
+
+	m[k] = v
+
+	return nil
+}
+
+// listFlag collects repeatable flags.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, " ")
+}
+
+func (l *listFlag) Set(s string) error {
+	*l = append(*l, s)
+
+	return nil
+}
+
+// config holds the command configuration.
+type config struct {
+	urls            listFlag
+	telegramToken   string
+	telegramChatIDs []string
+	stdout          bool
+	severity        string
+	appName         string
+	instanceName    string
+	timeout         time.Duration
+	meta            metaFlag
+}
+
+func parseFlags(args []string, stderr io.Writer) (config, []string, error) {
+	cfg := config{
+		urls: strings.Fields(getenv.EnvOrDefault(envURLs, "")),
+		meta: make(metaFlag),
+	}
+
+	fs := flag.NewFlagSet("notifier", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	fs.Var(&cfg.urls, "url", "Notifier URL, could be repeated [$"+envURLs+"]")
+	fs.StringVar(&cfg.telegramToken, "telegram-token",
+		getenv.EnvOrDefault(envTelegramToken, ""), "Telegram bot token [$"+envTelegramToken+"]")
+	chatIDs := fs.String("telegram-chat-ids",
+		strings.Join(getenv.EnvOrDefault(envTelegramChatIDs, []string{}), ","),
+		"Comma separated list of telegram chat ids [$"+envTelegramChatIDs+"]")
+	fs.BoolVar(&cfg.stdout, "stdout",
+		getenv.EnvOrDefault(envStdout, false), "Print alert to stdout [$"+envStdout+"]")
+	fs.StringVar(&cfg.severity, "severity",
+		getenv.EnvOrDefault(envSeverity, notifier.SeverityWarning.String()),
+		"Alert severity: DEBUG, INFO, WARNING, ERROR, CRITICAL or EMERGENCY [$"+envSeverity+"]")
+	fs.StringVar(&cfg.appName, "app",
+		getenv.EnvOrDefault(envAppName, ""), "Application name [$"+envAppName+"]")
+	fs.StringVar(&cfg.instanceName, "instance",
+		getenv.EnvOrDefault(envInstanceName, ""), "Instance name [$"+envInstanceName+"]")
+	fs.DurationVar(&cfg.timeout, "timeout",
+		getenv.EnvOrDefault(envTimeout, defaultTimeout), "Delivery timeout [$"+envTimeout+"]")
+	fs.Var(cfg.meta, "meta", "Metadata key=value pair, could be repeated")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(fs.Output(), "Usage: notifier [flags] [message]")
+		_, _ = fmt.Fprintln(fs.Output(), "The message is read from stdin if not passed as an argument or equal to \"-\".")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return config{}, nil, err
+	}
+
+	for _, id := range strings.Split(*chatIDs, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			cfg.telegramChatIDs = append(cfg.telegramChatIDs, id)
+		}
+	}
+
+	return cfg, fs.Args(), nil
+}
+
+// readMessage returns the message from the arguments or stdin.
+func readMessage(args []string, stdin io.Reader) (string, error) {
+	if len(args) > 0 && !(len(args) == 1 && args[0] == "-") {
+		return strings.Join(args, " "), nil
+	}
+
+	b, err := io.ReadAll(stdin)
+	if err != nil {
+		return "", fmt.Errorf("read stdin: %w", err)
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+// buildNotifier creates notifiers from the configuration.
+func buildNotifier(cfg config, stdout io.Writer) (notifier.Notifier, error) {
+	var notifiers []notifier.Notifier
+
+	for _, u := range cfg.urls {
+		n, err := notifier.FromURL(u)
+		if err != nil {
+			return nil, err
+		}
+
+		notifiers = append(notifiers, n)
+	}
+
+	for _, id := range cfg.telegramChatIDs {
+		n, err := notifier.NewTelegram(cfg.telegramToken, id)
+		if err != nil {
+			return nil, fmt.Errorf("create telegram notifier for chat '%s': %w", id, err)
+		}
+
+		notifiers = append(notifiers, n)
+	}
+
+	if cfg.stdout {
+		n, err := notifier.NewIOWriterNotifier(stdout, "stdout")
+		if err != nil {
+			return nil, fmt.Errorf("create stdout notifier: %w", err)
+		}
+
+		notifiers = append(notifiers, n)
+	}
+
+	if len(notifiers) == 0 {
+		return nil, errors.New("no notifiers configured: set notifier urls, telegram chat ids or enable stdout")
+	}
+
+	return notifier.NewMultiNotifier(notifiers...)
+}
+
+// buildExitCode returns the exit code for the buildNotifier error. The telegram client checks the token
+// over the network, so its failures are reported as delivery failures, the rest as invalid usage.
+func buildExitCode(err error) int {
+	var derr *notifier.DeliveryError
+
+	if errors.As(err, &derr) {
+		return exitFail
+	}
+
+	return exitUsage
+}
+
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cfg, rest, err := parseFlags(args, stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+
+		return exitUsage
+	}
+
+	severity, err := notifier.ParseSeverity(cfg.severity)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "notifier: %v\n", err)
+
+		return exitUsage
+	}
+
+	message, err := readMessage(rest, stdin)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "notifier: %v\n", err)
+
+		return exitUsage
+	}
+
+	n, err := buildNotifier(cfg, stdout)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "notifier: %v\n", err)
+
+		return buildExitCode(err)
+	}
+
+	ctx = notifier.ContextWithMetadata(ctx, notifier.Metadata{
+		AppName:      cfg.appName,
+		InstanceName: cfg.instanceName,
+		Extra:        cfg.meta,
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.timeout)
+	defer cancel()
+
+	if err = n.Alert(ctx, severity, message); err != nil {
+		_, _ = fmt.Fprintf(stderr, "notifier: %v\n", err)
+
+		if errors.Is(err, notifier.ErrEmptyMessage) || errors.Is(err, notifier.ErrInvalidSeverity) {
+			return exitUsage
+		}
+
+		return exitFail
+	}
+
+	return exitOK
+}
@@ -0,0 +1,688 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config describes a tree of notifiers.
+// It could be written in YAML or JSON:
+//
+//	metadata:
+//	  app_name: billing
+//	  extra:
+//	    env: ${ENV}
+//	notifiers:
+//	  - kind: telegram
+//	    params:
+//	      token_file: /run/secrets/telegram_token
+//	      chat_ids: "123,456"
+//	    decorators:
+//	      - kind: route
+//	        params:
+//	          min_severity: WARNING
+//	  - kind: iowriter
+//	    name: stdout
+//
+// All string values support ${ENV} expansion. Secret parameters (token, auth_token, account_sid and url)
+// could be set with the "_file" suffix instead, e.g. token_file, to read the value from the referenced file.
+type Config struct {
+	// Metadata is added to every alert. Fields already set in the alert context take precedence.
+	Metadata MetadataConfig `yaml:"metadata" json:"metadata"`
+	// Notifiers to send alerts to. Multiple notifiers are combined with NewMultiNotifier.
+	Notifiers []NotifierConfig `yaml:"notifiers" json:"notifiers"`
+}
+
+// MetadataConfig is the configuration of the default alert Metadata.
+type MetadataConfig struct {
+	AppName      string            `yaml:"app_name" json:"app_name"`
+	InstanceName string            `yaml:"instance_name" json:"instance_name"`
+	Commit       string            `yaml:"commit" json:"commit"`
+	BuildDate    string            `yaml:"build_date" json:"build_date"`
+	Extra        map[string]string `yaml:"extra" json:"extra"`
+}
+
+// NotifierConfig describes a single notifier.
+type NotifierConfig struct {
+	// Kind of the notifier: multi (see ParseDeliveryPolicy), failover, url (see FromURL) or any URL scheme
+	// registered with Register, e.g. telegram, iowriter, sms or oncall.
+	Kind string `yaml:"kind" json:"kind"`
+	// Name of the notifier, used to distinguish notifiers of the same kind.
+	Name string `yaml:"name" json:"name"`
+	// Params are kind specific parameters. For the registered URL schemes, they are the URL query params.
+	Params map[string]string `yaml:"params" json:"params"`
+	// Notifiers are the children of composite kinds, such as multi and failover.
+	Notifiers []NotifierConfig `yaml:"notifiers" json:"notifiers"`
+	// Decorators wrap the notifier in the order they are declared.
+	Decorators []DecoratorConfig `yaml:"decorators" json:"decorators"`
+}
+
+// DecoratorConfig describes a notifier decorator.
+type DecoratorConfig struct {
+	// Kind of the decorator: route, metadata, runtime, silence, group, circuit_breaker or any kind added with RegisterConfigDecorator.
+	Kind string `yaml:"kind" json:"kind"`
+	// Params are kind specific parameters.
+	Params map[string]string `yaml:"params" json:"params"`
+}
+
+// ConfigDecoratorBuilder wraps the notifier with a decorator created from the configuration parameters.
+type ConfigDecoratorBuilder func(next Notifier, params map[string]string) (Notifier, error)
+
+var (
+	configMu         sync.RWMutex
+	configDecorators = map[string]ConfigDecoratorBuilder{
+		"route":           buildRouteConfig,
+		"metadata":        buildMetadataConfig,
+		"runtime":         buildRuntimeConfig,
+		"silence":         buildSilenceConfig,
+		"group":           buildGroupConfig,
+		"circuit_breaker": buildCircuitBreakerConfig,
+	}
+)
+
+// RegisterConfigDecorator registers a decorator kind available in the configuration.
+// Registering the same kind twice replaces the previous builder.
+func RegisterConfigDecorator(kind string, b ConfigDecoratorBuilder) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	configDecorators[kind] = b
+}
+
+// LoadConfig reads the YAML or JSON configuration file and returns a ready notifier.
+func LoadConfig(path string) (Notifier, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	cfg, err := ParseConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.Build()
+}
+
+// ParseConfig parses the YAML or JSON configuration.
+// Environment variables and secret files are resolved during parsing.
+func ParseConfig(data []byte) (*Config, error) {
+	var cfg Config
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("decode config: %w", errors.Join(ErrInvalidConfig, err))
+	}
+
+	if err := cfg.resolve(); err != nil {
+		return nil, fmt.Errorf("resolve config: %w", errors.Join(ErrInvalidConfig, err))
+	}
+
+	return &cfg, nil
+}
+
+// Build creates the notifier described by the configuration.
+func (c *Config) Build() (Notifier, error) {
+	if len(c.Notifiers) == 0 {
+		return nil, ErrEmptyNotifiers
+	}
+
+	notifiers := make([]Notifier, 0, len(c.Notifiers))
+
+	for i := range c.Notifiers {
+		n, err := c.Notifiers[i].Build()
+		if err != nil {
+			return nil, err
+		}
+
+		notifiers = append(notifiers, n)
+	}
+
+	n := notifiers[0]
+
+	if len(notifiers) > 1 {
+		var err error
+
+		n, err = NewMultiNotifier(notifiers...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	md := c.Metadata.metadata()
+	if len(md.fields()) == 0 {
+		return n, nil
+	}
+
+	return &metadataNotifier{
+		next:     n,
+		metadata: md,
+	}, nil
+}
+
+// Build creates the notifier described by the configuration.
+func (c *NotifierConfig) Build() (Notifier, error) {
+	n, err := c.build()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range c.Decorators {
+		configMu.RLock()
+		decorate, ok := configDecorators[d.Kind]
+		configMu.RUnlock()
+
+		if !ok {
+			return nil, fmt.Errorf("decorator kind '%s': %w", d.Kind, ErrUnknownConfigKind)
+		}
+
+		n, err = decorate(n, d.Params)
+		if err != nil {
+			return nil, fmt.Errorf("decorate '%s' notifier with '%s': %w", c.Kind, d.Kind, err)
+		}
+	}
+
+	return n, nil
+}
+
+func (m MetadataConfig) metadata() Metadata {
+	return Metadata{
+		AppName:      m.AppName,
+		InstanceName: m.InstanceName,
+		Commit:       m.Commit,
+		BuildDate:    m.BuildDate,
+		Extra:        m.Extra,
+	}
+}
+
+// envRegex matches ${ENV} references.
+var envRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv replaces ${ENV} references with the environment variables values.
+func expandEnv(s string) (string, error) {
+	var errs []error
+
+	res := envRegex.ReplaceAllStringFunc(s, func(ref string) string {
+		key := envRegex.FindStringSubmatch(ref)[1]
+
+		v, ok := os.LookupEnv(key)
+		if !ok {
+			errs = append(errs, fmt.Errorf("environment variable '%s' is not set", key))
+		}
+
+		return v
+	})
+
+	return res, errors.Join(errs...)
+}
+
+// secretFileSuffix marks secret parameters which values should be read from a file.
+const secretFileSuffix = "_file"
+
+// secretParams are the parameters which could be read from a file with secretFileSuffix.
+// Other parameters with the suffix, e.g. log_file, are passed as is.
+var secretParams = []string{"token", "auth_token", "account_sid", "url"}
+
+// resolveParams expands environment variables and reads secret files.
+func resolveParams(params map[string]string) (map[string]string, error) {
+	if len(params) == 0 {
+		return params, nil
+	}
+
+	resolved := make(map[string]string, len(params))
+
+	var errs []error
+
+	for k, v := range params {
+		v, err := expandEnv(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("param '%s': %w", k, err))
+
+			continue
+		}
+
+		key, ok := strings.CutSuffix(k, secretFileSuffix)
+		if !ok || !slices.Contains(secretParams, key) {
+			resolved[k] = v
+
+			continue
+		}
+
+		secret, err := os.ReadFile(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("param '%s': read secret: %w", k, err))
+
+			continue
+		}
+
+		resolved[key] = strings.TrimSpace(string(secret))
+	}
+
+	return resolved, errors.Join(errs...)
+}
+
+func (c *Config) resolve() error {
+	var errs []error
+
+	md, err := resolveParams(map[string]string{
+		"app_name":      c.Metadata.AppName,
+		"instance_name": c.Metadata.InstanceName,
+		"commit":        c.Metadata.Commit,
+		"build_date":    c.Metadata.BuildDate,
+	})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("metadata: %w", err))
+	}
+
+	c.Metadata.AppName = md["app_name"]
+	c.Metadata.InstanceName = md["instance_name"]
+	c.Metadata.Commit = md["commit"]
+	c.Metadata.BuildDate = md["build_date"]
+
+	if c.Metadata.Extra, err = resolveParams(c.Metadata.Extra); err != nil {
+		errs = append(errs, fmt.Errorf("metadata extra: %w", err))
+	}
+
+	for i := range c.Notifiers {
+		if err = c.Notifiers[i].resolve(); err != nil {
+			errs = append(errs, fmt.Errorf("notifier[%d]: %w", i, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *NotifierConfig) resolve() error {
+	var errs []error
+
+	var err error
+
+	if c.Params, err = resolveParams(c.Params); err != nil {
+		errs = append(errs, err)
+	}
+
+	for i := range c.Decorators {
+		if c.Decorators[i].Params, err = resolveParams(c.Decorators[i].Params); err != nil {
+			errs = append(errs, fmt.Errorf("decorator[%d]: %w", i, err))
+		}
+	}
+
+	for i := range c.Notifiers {
+		if err = c.Notifiers[i].resolve(); err != nil {
+			errs = append(errs, fmt.Errorf("notifier[%d]: %w", i, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// splitList splits comma separated list, skipping empty values.
+func splitList(s string) []string {
+	var list []string
+
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
+
+// requireParams checks that all keys are set in params.
+func requireParams(params map[string]string, keys ...string) error {
+	var missed []string
+
+	for _, k := range keys {
+		if params[k] == "" {
+			missed = append(missed, k)
+		}
+	}
+
+	if len(missed) == 0 {
+		return nil
+	}
+
+	sort.Strings(missed)
+
+	return fmt.Errorf("params '%s' are required: %w", strings.Join(missed, ", "), ErrInvalidConfig)
+}
+
+// build creates the notifier of the kind, without decorators.
+func (c *NotifierConfig) build() (Notifier, error) {
+	var (
+		n   Notifier
+		err error
+	)
+
+	switch c.Kind {
+	case "multi", "failover":
+		children := make([]Notifier, 0, len(c.Notifiers))
+
+		for i := range c.Notifiers {
+			var child Notifier
+
+			child, err = c.Notifiers[i].Build()
+			if err != nil {
+				return nil, err
+			}
+
+			children = append(children, child)
+		}
+
+		if c.Kind == "multi" {
+			n, err = buildMultiConfig(c.Params, children)
+		} else {
+			n, err = buildFailoverConfig(children)
+		}
+	case "url":
+		if err = requireParams(c.Params, "url"); err == nil {
+			n, err = FromURL(c.Params["url"])
+		}
+	default:
+		f, ok := lookupFactory(c.Kind)
+		if !ok {
+			return nil, fmt.Errorf("notifier kind '%s': %w", c.Kind, ErrUnknownConfigKind)
+		}
+
+		if len(c.Notifiers) > 0 {
+			return nil, fmt.Errorf("notifier kind '%s' has no children: %w", c.Kind, ErrInvalidConfig)
+		}
+
+		n, err = f(c.url())
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("build '%s' notifier: %w", c.Kind, err)
+	}
+
+	return n, nil
+}
+
+// url returns the URL the notifier of the registered kind is created with: the name and params are the query.
+func (c *NotifierConfig) url() *url.URL {
+	q := make(url.Values, len(c.Params)+1)
+
+	for k, v := range c.Params {
+		q.Set(k, v)
+	}
+
+	if c.Name != "" {
+		q.Set("name", c.Name)
+	}
+
+	return &url.URL{
+		Scheme:   c.Kind,
+		RawQuery: q.Encode(),
+	}
+}
+
+// buildMultiConfig builds multi notifier from the children.
+// Params: policy (all, any, best_effort or at_least:N) and continue_on_invalid.
+func buildMultiConfig(params map[string]string, children []Notifier) (Notifier, error) {
+	var policy DeliveryPolicy
+
+	for k, v := range params {
+		var (
+			err       error
+			continued bool
+		)
+
+		switch k {
+		case "policy":
+			continued = policy.ContinueOnInvalid
+			policy, err = ParseDeliveryPolicy(v)
+			policy.ContinueOnInvalid = continued
+		case "continue_on_invalid":
+			policy.ContinueOnInvalid, err = strconv.ParseBool(v)
+		default:
+			return nil, fmt.Errorf("unknown param '%s': %w", k, ErrInvalidConfig)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("param '%s': %w: %w", k, ErrInvalidConfig, err)
+		}
+	}
+
+	return NewMultiNotifierWithPolicy(policy, children...)
+}
+
+// buildFailoverConfig builds the failover notifier trying the children in order.
+func buildFailoverConfig(children []Notifier) (Notifier, error) {
+	if len(children) == 0 {
+		return nil, ErrEmptyNotifiers
+	}
+
+	return NewFailover(children[0], children[1:]...)
+}
+
+// buildRouteConfig builds the route decorator.
+// Params: min_severity, severities (comma separated) and match.<key> metadata values.
+func buildRouteConfig(next Notifier, params map[string]string) (Notifier, error) {
+	r := &routeNotifier{
+		next:        next,
+		minSeverity: severityUnknown,
+		severities:  nil,
+		match:       make(map[string]string),
+	}
+
+	for k, v := range params {
+		switch {
+		case k == "min_severity":
+			s, err := ParseSeverity(v)
+			if err != nil {
+				return nil, err
+			}
+
+			r.minSeverity = s
+		case k == "severities":
+			for _, name := range splitList(v) {
+				s, err := ParseSeverity(name)
+				if err != nil {
+					return nil, err
+				}
+
+				r.severities = append(r.severities, s)
+			}
+		case strings.HasPrefix(k, "match."):
+			r.match[strings.TrimPrefix(k, "match.")] = v
+		default:
+			return nil, fmt.Errorf("unknown param '%s': %w", k, ErrInvalidConfig)
+		}
+	}
+
+	return r, nil
+}
+
+// buildMetadataConfig builds the metadata decorator.
+// Params: app_name, instance_name, commit, build_date, any other keys are added as extra fields.
+func buildMetadataConfig(next Notifier, params map[string]string) (Notifier, error) {
+	md := Metadata{
+		Extra: make(map[string]string),
+	}
+
+	for k, v := range params {
+		switch k {
+		case "app_name":
+			md.AppName = v
+		case "instance_name":
+			md.InstanceName = v
+		case "commit":
+			md.Commit = v
+		case "build_date":
+			md.BuildDate = v
+		default:
+			md.Extra[k] = v
+		}
+	}
+
+	return &metadataNotifier{
+		next:     next,
+		metadata: md,
+	}, nil
+}
+
+// buildRuntimeConfig builds the runtime metadata enricher. It has no params.
+func buildRuntimeConfig(next Notifier, _ map[string]string) (Notifier, error) {
+	return NewRuntimeEnricher(next)
+}
+
+// buildSilenceConfig builds the silencer.
+// Params: path of the file the silences are persisted to (optional).
+func buildSilenceConfig(next Notifier, params map[string]string) (Notifier, error) {
+	for k := range params {
+		if k != "path" {
+			return nil, fmt.Errorf("unknown param '%s': %w", k, ErrInvalidConfig)
+		}
+	}
+
+	return NewSilencer(next, &SilencerOptions{Path: params["path"]})
+}
+
+// buildGroupConfig builds the grouper.
+// Params: group_by (comma separated), wait, interval, max_batch and flush_severity.
+func buildGroupConfig(next Notifier, params map[string]string) (Notifier, error) {
+	var opts GroupOptions
+
+	for k, v := range params {
+		var err error
+
+		switch k {
+		case "group_by":
+			opts.GroupBy = splitList(v)
+		case "wait":
+			opts.Wait, err = time.ParseDuration(v)
+		case "interval":
+			opts.Interval, err = time.ParseDuration(v)
+		case "max_batch":
+			opts.MaxBatch, err = strconv.Atoi(v)
+		case "flush_severity":
+			opts.FlushSeverity, err = ParseSeverity(v)
+		default:
+			return nil, fmt.Errorf("unknown param '%s': %w", k, ErrInvalidConfig)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("param '%s': %w: %w", k, ErrInvalidConfig, err)
+		}
+	}
+
+	return NewGrouper(next, &opts)
+}
+
+// buildCircuitBreakerConfig builds the circuit breaker.
+// Params: failure_threshold and open_timeout.
+func buildCircuitBreakerConfig(next Notifier, params map[string]string) (Notifier, error) {
+	var opts CircuitBreakerOptions
+
+	for k, v := range params {
+		var err error
+
+		switch k {
+		case "failure_threshold":
+			opts.FailureThreshold, err = strconv.Atoi(v)
+		case "open_timeout":
+			opts.OpenTimeout, err = time.ParseDuration(v)
+		default:
+			return nil, fmt.Errorf("unknown param '%s': %w", k, ErrInvalidConfig)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("param '%s': %w: %w", k, ErrInvalidConfig, err)
+		}
+	}
+
+	return NewCircuitBreaker(next, &opts)
+}
+
+// routeNotifier forwards only alerts matching the route.
+type routeNotifier struct {
+	next        Notifier
+	minSeverity Severity
+	severities  []Severity
+	match       map[string]string
+}
+
+// Kind returns the notifier kind.
+func (r *routeNotifier) Kind() string {
+	return r.next.Kind()
+}
+
+// Alert sends the alert if it matches the route.
+func (r *routeNotifier) Alert(ctx context.Context, severity Severity, message string) error {
+	if err := validateAlert(severity, message); err != nil {
+		return err
+	}
+
+	if !r.matches(ctx, severity) {
+		return nil
+	}
+
+	return r.next.Alert(ctx, severity, message)
+}
+
+func (r *routeNotifier) matches(ctx context.Context, severity Severity) bool {
+	if severity < r.minSeverity {
+		return false
+	}
+
+	if len(r.severities) > 0 && !slices.Contains(r.severities, severity) {
+		return false
+	}
+
+	if len(r.match) == 0 {
+		return true
+	}
+
+	var fields map[string]string
+
+	if m, ok := MetadataFromContext(ctx); ok {
+		fields = fieldsMap(m.fields())
+	}
+
+	for k, v := range r.match {
+		if fields[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// metadataNotifier adds default metadata to every alert.
+type metadataNotifier struct {
+	next     Notifier
+	metadata Metadata
+}
+
+// Kind returns the notifier kind.
+func (m *metadataNotifier) Kind() string {
+	return m.next.Kind()
+}
+
+// Alert sends the alert with the default metadata merged under the context metadata.
+func (m *metadataNotifier) Alert(ctx context.Context, severity Severity, message string) error {
+	md := m.metadata
+
+	if cur, ok := MetadataFromContext(ctx); ok {
+		md = mergeMetadata(md, *cur)
+	}
+
+	return m.next.Alert(contextWithMetadata(ctx, md), severity, message)
+}
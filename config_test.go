@@ -0,0 +1,185 @@
+package notifier_test
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/notifier"
+)
+
+func TestLoadConfig(t *testing.T) {
+	var buf bytes.Buffer
+
+	notifier.Register("test_buffer", func(u *url.URL) (notifier.Notifier, error) {
+		q := u.Query()
+
+		return notifier.NewIOWriterNotifier(&buf, strings.Join(strings.Fields(q.Get("name")+" "+q.Get("token")+" "+q.Get("log_file")), " "))
+	})
+
+	dir := t.TempDir()
+
+	secret := filepath.Join(dir, "secret")
+	require.NoError(t, os.WriteFile(secret, []byte("s3cr3t\n"), 0o600))
+
+	t.Setenv("TEST_CONFIG_ENV", "staging")
+	t.Setenv("TEST_CONFIG_SECRET", secret)
+
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+metadata:
+  app_name: test_app
+  extra:
+    env: ${TEST_CONFIG_ENV}
+notifiers:
+  - kind: multi
+    notifiers:
+      - kind: test_buffer
+        name: all
+        params:
+          token_file: ${TEST_CONFIG_SECRET}
+      - kind: test_buffer
+        name: critical
+        params:
+          log_file: /var/log/app.log
+        decorators:
+          - kind: route
+            params:
+              min_severity: critical
+          - kind: metadata
+            params:
+              team: ops
+`), 0o600))
+
+	n, err := notifier.LoadConfig(path)
+	require.NoError(t, err)
+
+	// Only secret params are read from files.
+	assert.Equal(t, "multi[iowriter: all s3cr3t;iowriter: critical /var/log/app.log]", n.Kind())
+
+	ctx := notifier.ContextWithMetadata(context.Background(), notifier.Metadata{InstanceName: "test_instance"})
+
+	require.NoError(t, n.Alert(ctx, notifier.SeverityWarning, "warning message"))
+
+	got := buf.String()
+	assert.Contains(t, got, "warning message")
+	assert.Contains(t, got, "• app_name: test_app")
+	assert.Contains(t, got, "• env: staging")
+	assert.Contains(t, got, "• instance_name: test_instance")
+	assert.NotContains(t, got, "team")
+
+	buf.Reset()
+
+	require.NoError(t, n.Alert(ctx, notifier.SeverityCritical, "critical message"))
+
+	got = buf.String()
+	assert.Contains(t, got, "• team: ops")
+	assert.Contains(t, got, "• app_name: test_app")
+}
+
+func TestParseConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{
+			name:    "unknown field",
+			data:    `{"notifiers": [{"kind": "iowriter", "unknown": true}]}`,
+			wantErr: notifier.ErrInvalidConfig,
+		},
+		{
+			name:    "env is not set",
+			data:    `{"notifiers": [{"kind": "telegram", "params": {"token": "${TEST_CONFIG_NOT_SET}"}}]}`,
+			wantErr: notifier.ErrInvalidConfig,
+		},
+		{
+			name:    "secret file not found",
+			data:    `{"notifiers": [{"kind": "telegram", "params": {"token_file": "/not/exist"}}]}`,
+			wantErr: notifier.ErrInvalidConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := notifier.ParseConfig([]byte(tt.data))
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestConfig_BuildErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{
+			name:    "no notifiers",
+			data:    `{}`,
+			wantErr: notifier.ErrEmptyNotifiers,
+		},
+		{
+			name:    "unknown kind",
+			data:    `{"notifiers": [{"kind": "pigeon"}]}`,
+			wantErr: notifier.ErrUnknownConfigKind,
+		},
+		{
+			name:    "unknown decorator",
+			data:    `{"notifiers": [{"kind": "iowriter", "decorators": [{"kind": "unknown"}]}]}`,
+			wantErr: notifier.ErrUnknownConfigKind,
+		},
+		{
+			name:    "invalid route severity",
+			data:    `{"notifiers": [{"kind": "iowriter", "decorators": [{"kind": "route", "params": {"min_severity": "fatal"}}]}]}`,
+			wantErr: notifier.ErrInvalidSeverity,
+		},
+		{
+			name:    "telegram without token",
+			data:    `{"notifiers": [{"kind": "telegram", "params": {"chat_id": "1"}}]}`,
+			wantErr: notifier.ErrInvalidConfig,
+		},
+		{
+			name:    "children of leaf kind",
+			data:    `{"notifiers": [{"kind": "iowriter", "notifiers": [{"kind": "iowriter"}]}]}`,
+			wantErr: notifier.ErrInvalidConfig,
+		},
+		{
+			name:    "unknown multi policy",
+			data:    `{"notifiers": [{"kind": "multi", "params": {"policy": "most"}, "notifiers": [{"kind": "iowriter"}]}]}`,
+			wantErr: notifier.ErrInvalidPolicy,
+		},
+		{
+			name:    "multi quorum above notifiers",
+			data:    `{"notifiers": [{"kind": "multi", "params": {"policy": "at_least:2"}, "notifiers": [{"kind": "iowriter"}]}]}`,
+			wantErr: notifier.ErrInvalidPolicy,
+		},
+		{
+			name:    "unknown multi policy",
+			data:    `{"notifiers": [{"kind": "multi", "params": {"policy": "most"}, "notifiers": [{"kind": "iowriter"}]}]}`,
+			wantErr: notifier.ErrInvalidPolicy,
+		},
+		{
+			name:    "multi quorum above notifiers",
+			data:    `{"notifiers": [{"kind": "multi", "params": {"policy": "at_least:2"}, "notifiers": [{"kind": "iowriter"}]}]}`,
+			wantErr: notifier.ErrInvalidPolicy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := notifier.ParseConfig([]byte(tt.data))
+			require.NoError(t, err)
+
+			_, err = cfg.Build()
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
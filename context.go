@@ -2,56 +2,167 @@
 
 import (
 	"context"
+	"sort"
 )
 
 type ctxKey struct{}
 
 // Metadata contains information about the application.
+//
+// Fields are rendered in a fixed order: built-in fields (app name, instance name, commit, build date),
+// then Extra sorted by key, then Fields in insertion order. Grouped fields are rendered after
+// the ungrouped ones, groups are ordered by their first appearance. Empty values are skipped.
 type Metadata struct {
 	AppName      string
 	InstanceName string
 	Commit       string
 	BuildDate    string
-	Extra        map[string]string
+	// Extra holds additional fields, rendered sorted by key.
+	Extra map[string]string
+	// Fields holds additional fields, rendered in insertion order.
+	// A field with the key that is already set overrides its value.
+	Fields []MetadataField
+	// Labels holds display labels by field key, e.g. "app_name": "Application".
+	Labels map[string]string
 }
 
-func (m Metadata) toMap() map[string]string {
-	const defaultMapSize = 5
+// MetadataField is a single metadata key-value pair.
+type MetadataField struct {
+	Key   string
+	Value string
+	// Label is the display name of the field. If empty, Key is displayed.
+	Label string
+	// Group is the name of the group the field is rendered in.
+	Group string
+}
+
+// Name returns the display name of the field.
+func (f MetadataField) Name() string {
+	if f.Label != "" {
+		return f.Label
+	}
+
+	return f.Key
+}
+
+// Built-in metadata keys.
+const (
+	metadataAppName      = "app_name"
+	metadataInstanceName = "instance_name"
+	metadataCommit       = "commit"
+	metadataBuildDate    = "build_date"
+)
+
+// fields returns the non-empty metadata fields in the rendering order.
+func (m Metadata) fields() []MetadataField {
+	const builtinFields = 4
 
-	metadataMap := make(map[string]string, defaultMapSize)
+	fields := make([]MetadataField, 0, builtinFields+len(m.Extra)+len(m.Fields))
+	index := make(map[string]int, cap(fields))
+
+	add := func(f MetadataField) {
+		if f.Value == "" {
+			return
+		}
+
+		if f.Label == "" {
+			f.Label = m.Labels[f.Key]
+		}
+
+		if i, ok := index[f.Key]; ok {
+			fields[i] = f
+
+			return
+		}
 
-	if m.AppName != "" {
-		metadataMap["app_name"] = m.AppName
+		index[f.Key] = len(fields)
+		fields = append(fields, f)
 	}
 
-	if m.InstanceName != "" {
-		metadataMap["instance_name"] = m.InstanceName
+	add(MetadataField{Key: metadataAppName, Value: m.AppName})
+	add(MetadataField{Key: metadataInstanceName, Value: m.InstanceName})
+	add(MetadataField{Key: metadataCommit, Value: m.Commit})
+	add(MetadataField{Key: metadataBuildDate, Value: m.BuildDate})
+
+	extraKeys := make([]string, 0, len(m.Extra))
+
+	for k := range m.Extra {
+		extraKeys = append(extraKeys, k)
 	}
 
-	if m.Commit != "" {
-		metadataMap["commit"] = m.Commit
+	sort.Strings(extraKeys)
+
+	for _, k := range extraKeys {
+		add(MetadataField{Key: k, Value: m.Extra[k]})
 	}
 
-	if m.BuildDate != "" {
-		metadataMap["build_date"] = m.BuildDate
+	for _, f := range m.Fields {
+		add(f)
 	}
 
-	if m.Extra != nil {
-		for k, v := range m.Extra {
-			metadataMap[k] = v
+	return groupFields(fields)
+}
+
+// groupFields moves grouped fields after ungrouped ones, keeping groups in order of first appearance.
+func groupFields(fields []MetadataField) []MetadataField {
+	rank := make(map[string]int)
+
+	for _, f := range fields {
+		if _, ok := rank[f.Group]; !ok && f.Group != "" {
+			rank[f.Group] = len(rank) + 1
 		}
 	}
 
-	return metadataMap
+	if len(rank) == 0 {
+		return fields
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		return rank[fields[i].Group] < rank[fields[j].Group]
+	})
+
+	return fields
 }
 
-// ContextWithMetadata returns a new context with the given metadata.
+// fieldsMap returns the fields as a map by key.
+func fieldsMap(fields []MetadataField) map[string]string {
+	m := make(map[string]string, len(fields))
+
+	for _, f := range fields {
+		m[f.Key] = f.Value
+	}
+
+	return m
+}
+
+// ContextWithMetadata returns a new context with the given metadata merged over the metadata
+// already stored in ctx: non-empty built-in fields override the outer ones, Extra and Labels
+// are merged by key, Fields are appended.
 // If ctx is nil, context.Background() is used.
 func ContextWithMetadata(ctx context.Context, metadata Metadata) context.Context {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
+	if cur, ok := MetadataFromContext(ctx); ok {
+		metadata = mergeMetadata(*cur, metadata)
+	}
+
+	return contextWithMetadata(ctx, metadata)
+}
+
+// ContextWithField returns a new context with the field added to the metadata stored in ctx.
+func ContextWithField(ctx context.Context, key, value string) context.Context {
+	return ContextWithFields(ctx, MetadataField{Key: key, Value: value})
+}
+
+// ContextWithFields returns a new context with the fields added to the metadata stored in ctx.
+func ContextWithFields(ctx context.Context, fields ...MetadataField) context.Context {
+	return ContextWithMetadata(ctx, Metadata{Fields: fields})
+}
+
+// contextWithMetadata returns a new context with the metadata replacing the one stored in ctx.
+func contextWithMetadata(ctx context.Context, metadata Metadata) context.Context {
 	return context.WithValue(ctx, ctxKey{}, &metadata)
 }
 
@@ -68,3 +179,53 @@ func MetadataFromContext(ctx context.Context) (*Metadata, bool) {
 
 	return metadata, true
 }
+
+// mergeMetadata returns base metadata overridden by non-empty fields of the override.
+func mergeMetadata(base, override Metadata) Metadata {
+	res := base
+
+	if override.AppName != "" {
+		res.AppName = override.AppName
+	}
+
+	if override.InstanceName != "" {
+		res.InstanceName = override.InstanceName
+	}
+
+	if override.Commit != "" {
+		res.Commit = override.Commit
+	}
+
+	if override.BuildDate != "" {
+		res.BuildDate = override.BuildDate
+	}
+
+	res.Extra = mergeMaps(base.Extra, override.Extra)
+	res.Labels = mergeMaps(base.Labels, override.Labels)
+
+	if len(override.Fields) > 0 {
+		res.Fields = append(append(make([]MetadataField, 0, len(base.Fields)+len(override.Fields)),
+			base.Fields...), override.Fields...)
+	}
+
+	return res
+}
+
+// mergeMaps returns a new map with values of override set over base.
+func mergeMaps(base, override map[string]string) map[string]string {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+
+	res := make(map[string]string, len(base)+len(override))
+
+	for k, v := range base {
+		res[k] = v
+	}
+
+	for k, v := range override {
+		res[k] = v
+	}
+
+	return res
+}
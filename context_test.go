@@ -1,66 +1,139 @@
 package notifier
 
 import (
-	"fmt"
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func TestMetadata_toMap(t *testing.T) {
-	type fields struct {
-		AppName      string
-		InstanceName string
-		Commit       string
-		BuildDate    string
-	}
-
+func TestMetadata_fields(t *testing.T) {
 	tests := []struct {
-		name    string
-		fields  fields
-		want    map[string]string
-		wantLen int
+		name     string
+		metadata Metadata
+		want     []MetadataField
 	}{
 		{
 			name: "all fields are empty",
-			fields: fields{
+			metadata: Metadata{
 				AppName:      "",
 				InstanceName: "",
 				Commit:       "",
 				BuildDate:    "",
 			},
-			want:    map[string]string{},
-			wantLen: 0,
+			want: []MetadataField{},
 		},
 		{
 			name: "app name is set and commit",
-			fields: fields{
+			metadata: Metadata{
 				AppName:      "test",
 				InstanceName: "",
 				Commit:       "123",
 				BuildDate:    "",
 			},
-			want: map[string]string{
-				"app_name": "test",
-				"commit":   "123",
+			want: []MetadataField{
+				{Key: "app_name", Value: "test"},
+				{Key: "commit", Value: "123"},
+			},
+		},
+		{
+			name: "built-in fields first, extra sorted, fields in insertion order",
+			metadata: Metadata{
+				AppName:      "test",
+				InstanceName: "instance",
+				Commit:       "123",
+				BuildDate:    "2020-01-01",
+				Extra: map[string]string{
+					"zone":  "eu",
+					"empty": "",
+					"env":   "prod",
+				},
+				Fields: []MetadataField{
+					{Key: "user", Value: "john"},
+					{Key: "request_id", Value: "42"},
+					{Key: "zone", Value: "us"},
+				},
+			},
+			want: []MetadataField{
+				{Key: "app_name", Value: "test"},
+				{Key: "instance_name", Value: "instance"},
+				{Key: "commit", Value: "123"},
+				{Key: "build_date", Value: "2020-01-01"},
+				{Key: "env", Value: "prod"},
+				{Key: "zone", Value: "us"},
+				{Key: "user", Value: "john"},
+				{Key: "request_id", Value: "42"},
+			},
+		},
+		{
+			name: "labels and groups",
+			metadata: Metadata{
+				AppName: "test",
+				Fields: []MetadataField{
+					{Key: "method", Value: "GET", Group: "Request"},
+					{Key: "user", Value: "john"},
+					{Key: "db", Value: "postgres", Group: "Dependencies"},
+					{Key: "status", Value: "500", Label: "Status", Group: "Request"},
+				},
+				Labels: map[string]string{
+					"app_name": "Application",
+					"method":   "Method",
+				},
+			},
+			want: []MetadataField{
+				{Key: "app_name", Value: "test", Label: "Application"},
+				{Key: "user", Value: "john"},
+				{Key: "method", Value: "GET", Label: "Method", Group: "Request"},
+				{Key: "status", Value: "500", Label: "Status", Group: "Request"},
+				{Key: "db", Value: "postgres", Group: "Dependencies"},
 			},
-			wantLen: 2,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			m := Metadata{
-				AppName:      tt.fields.AppName,
-				InstanceName: tt.fields.InstanceName,
-				Commit:       tt.fields.Commit,
-				BuildDate:    tt.fields.BuildDate,
-			}
-
-			got := m.toMap()
-
-			assert.Equal(t, fmt.Sprintf("%#v", tt.want), fmt.Sprintf("%#v", got))
-			assert.Equal(t, tt.wantLen, len(got))
+			assert.Equal(t, tt.want, tt.metadata.fields())
 		})
 	}
 }
+
+func TestContextWithMetadata_nested(t *testing.T) {
+	ctx := ContextWithMetadata(context.Background(), Metadata{
+		AppName: "test_app",
+		Commit:  "123",
+		Extra:   map[string]string{"env": "prod", "zone": "eu"},
+	})
+
+	reqCtx := ContextWithMetadata(ctx, Metadata{
+		InstanceName: "instance",
+		Extra:        map[string]string{"zone": "us"},
+	})
+	reqCtx = ContextWithField(reqCtx, "request_id", "42")
+	reqCtx = ContextWithFields(reqCtx,
+		MetadataField{Key: "user", Value: "john"},
+		MetadataField{Key: "request_id", Value: "43"},
+	)
+
+	md, ok := MetadataFromContext(reqCtx)
+	require.True(t, ok)
+
+	assert.Equal(t, []MetadataField{
+		{Key: "app_name", Value: "test_app"},
+		{Key: "instance_name", Value: "instance"},
+		{Key: "commit", Value: "123"},
+		{Key: "env", Value: "prod"},
+		{Key: "zone", Value: "us"},
+		{Key: "request_id", Value: "43"},
+		{Key: "user", Value: "john"},
+	}, md.fields())
+
+	outer, ok := MetadataFromContext(ctx)
+	require.True(t, ok)
+
+	assert.Equal(t, Metadata{
+		AppName: "test_app",
+		Commit:  "123",
+		Extra:   map[string]string{"env": "prod", "zone": "eu"},
+	}, *outer)
+}
@@ -0,0 +1,179 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// DeliveryError is returned by notifiers when the alert could not be delivered to the destination.
+// It matches ErrRateLimited, ErrUnauthorized and ErrPayloadTooLarge with errors.Is by the status code.
+type DeliveryError struct {
+	// Kind of the notifier or sender that failed, e.g. telegram or twilio.
+	Kind string
+	// StatusCode is the HTTP or API status code. Zero if no response was received.
+	StatusCode int
+	// Retryable reports whether the delivery could succeed if retried later.
+	Retryable bool
+	// RetryAfter is the delay requested by the destination before the retry, if any.
+	RetryAfter time.Duration
+	// Err is the underlying error.
+	Err error
+
+	// payloadTooLarge is set when the destination reports the too large payload with another status code.
+	payloadTooLarge bool
+}
+
+// Error returns the error message. If Err is nil, the status text or the kind is used.
+func (e *DeliveryError) Error() string {
+	var msg string
+
+	switch {
+	case e.Err != nil:
+		msg = e.Err.Error()
+	case e.StatusCode != 0:
+		msg = http.StatusText(e.StatusCode)
+	case e.Kind != "":
+		msg = fmt.Sprintf("delivery to '%s' failed", e.Kind)
+	default:
+		msg = "delivery failed"
+	}
+
+	if e.StatusCode == 0 {
+		return msg
+	}
+
+	return fmt.Sprintf("unexpected status %d: %s", e.StatusCode, msg)
+}
+
+// Unwrap returns the underlying error.
+func (e *DeliveryError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the status code matches the target sentinel error.
+func (e *DeliveryError) Is(target error) bool {
+	switch target {
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrPayloadTooLarge:
+		return e.StatusCode == http.StatusRequestEntityTooLarge || e.payloadTooLarge
+	default:
+		return false
+	}
+}
+
+// IsRetryable reports whether the failed alert could be delivered if retried later.
+// DeliveryError reports it with its Retryable field, invalid alerts are never retryable and
+// other errors are assumed to be transient. Joined errors are retryable if any of them is.
+func IsRetryable(err error) bool {
+	switch e := err.(type) {
+	case nil:
+		return false
+	case *DeliveryError:
+		return e.Retryable
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			if IsRetryable(err) {
+				return true
+			}
+		}
+
+		return false
+	case interface{ Unwrap() error }:
+		if inner := e.Unwrap(); inner != nil {
+			return IsRetryable(inner)
+		}
+	}
+
+	return !errors.Is(err, ErrEmptyMessage) && !errors.Is(err, ErrInvalidSeverity)
+}
+
+// RetryAfter returns the longest delay before the retry requested by the destinations in err, zero if none.
+func RetryAfter(err error) time.Duration {
+	switch e := err.(type) {
+	case *DeliveryError:
+		return max(e.RetryAfter, RetryAfter(e.Err))
+	case interface{ Unwrap() []error }:
+		var d time.Duration
+
+		for _, err := range e.Unwrap() {
+			d = max(d, RetryAfter(err))
+		}
+
+		return d
+	case interface{ Unwrap() error }:
+		return RetryAfter(e.Unwrap())
+	default:
+		return 0
+	}
+}
+
+// newDeliveryError returns the DeliveryError of the response with the status code, or of the failed
+// request if the status code is zero.
+func newDeliveryError(ctx context.Context, kind string, statusCode int, retryAfter time.Duration, err error) *DeliveryError {
+	return &DeliveryError{
+		Kind:       kind,
+		StatusCode: statusCode,
+		Retryable:  isRetryableStatus(statusCode) && ctx.Err() == nil,
+		RetryAfter: retryAfter,
+		Err:        err,
+	}
+}
+
+// isRetryableStatus reports whether the request with the status code could succeed if retried.
+// Zero status code means a network error.
+func isRetryableStatus(code int) bool {
+	return code == 0 ||
+		code == http.StatusRequestTimeout ||
+		code == http.StatusTooManyRequests ||
+		code >= http.StatusInternalServerError
+}
+
+// parseRetryAfter parses the Retry-After header value in seconds or HTTP date format.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(secs)*time.Second, 0)
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0)
+	}
+
+	return 0
+}
+
+// telegramDeliveryError converts the telegram client error to DeliveryError.
+func telegramDeliveryError(ctx context.Context, err error) *DeliveryError {
+	var apiErr *tgbotapi.Error
+
+	if !errors.As(err, &apiErr) {
+		return newDeliveryError(ctx, "telegram", 0, 0, err)
+	}
+
+	// File uploads report API errors without the code, these are client errors.
+	code := apiErr.Code
+	if code == 0 {
+		code = http.StatusBadRequest
+	}
+
+	derr := newDeliveryError(ctx, "telegram", code, time.Duration(apiErr.RetryAfter)*time.Second, err)
+
+	// Telegram reports too long texts and captions as bad requests, e.g. "Bad Request: message is too long".
+	derr.payloadTooLarge = code == http.StatusBadRequest &&
+		strings.HasPrefix(apiErr.Message, "Bad Request:") && strings.HasSuffix(apiErr.Message, "is too long")
+
+	return derr
+}
@@ -0,0 +1,137 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTelegramDeliveryError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		wantStatus     int
+		wantRetryable  bool
+		wantRetryAfter time.Duration
+		wantIs         error
+	}{
+		{
+			name: "rate limited",
+			err: &tgbotapi.Error{
+				Code:               http.StatusTooManyRequests,
+				Message:            "Too Many Requests: retry after 5",
+				ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 5},
+			},
+			wantStatus:     http.StatusTooManyRequests,
+			wantRetryable:  true,
+			wantRetryAfter: 5 * time.Second,
+			wantIs:         ErrRateLimited,
+		},
+		{
+			name:       "unauthorized",
+			err:        &tgbotapi.Error{Code: http.StatusUnauthorized, Message: "Unauthorized"},
+			wantStatus: http.StatusUnauthorized,
+			wantIs:     ErrUnauthorized,
+		},
+		{
+			name:       "bot blocked",
+			err:        &tgbotapi.Error{Code: http.StatusForbidden, Message: "Forbidden: bot was blocked by the user"},
+			wantStatus: http.StatusForbidden,
+			wantIs:     ErrUnauthorized,
+		},
+		{
+			name:       "message too long",
+			err:        &tgbotapi.Error{Code: http.StatusBadRequest, Message: "Bad Request: message is too long"},
+			wantStatus: http.StatusBadRequest,
+			wantIs:     ErrPayloadTooLarge,
+		},
+		{
+			name:       "caption too long",
+			err:        &tgbotapi.Error{Code: http.StatusBadRequest, Message: "Bad Request: message caption is too long"},
+			wantStatus: http.StatusBadRequest,
+			wantIs:     ErrPayloadTooLarge,
+		},
+		{
+			name:          "server error",
+			err:           &tgbotapi.Error{Code: http.StatusBadGateway, Message: "Bad Gateway"},
+			wantStatus:    http.StatusBadGateway,
+			wantRetryable: true,
+		},
+		{
+			name:          "network error",
+			err:           errors.New("connection refused"),
+			wantRetryable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := telegramDeliveryError(context.Background(), tt.err)
+
+			assert.Equal(t, "telegram", err.Kind)
+			assert.Equal(t, tt.wantStatus, err.StatusCode)
+			assert.Equal(t, tt.wantRetryable, err.Retryable)
+			assert.Equal(t, tt.wantRetryable, IsRetryable(err))
+			assert.Equal(t, tt.wantRetryAfter, err.RetryAfter)
+			require.ErrorIs(t, err, tt.err)
+
+			if tt.wantIs != nil {
+				require.ErrorIs(t, err, tt.wantIs)
+			}
+
+			if !errors.Is(tt.wantIs, ErrPayloadTooLarge) {
+				require.NotErrorIs(t, err, ErrPayloadTooLarge)
+			}
+		})
+	}
+}
+
+func TestTelegramDeliveryError_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := telegramDeliveryError(ctx, context.Canceled)
+	assert.False(t, err.Retryable)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	assert.Equal(t, 30*time.Second, parseRetryAfter("30", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("-1", now))
+	assert.Equal(t, 2*time.Minute, parseRetryAfter("Mon, 01 Jan 2024 12:02:00 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+}
+
+func TestDeliveryError_Error(t *testing.T) {
+	assert.Equal(t, "unexpected status 429: Too Many Requests",
+		(&DeliveryError{StatusCode: http.StatusTooManyRequests}).Error())
+	assert.Equal(t, "delivery to 'twilio' failed", (&DeliveryError{Kind: "twilio"}).Error())
+	assert.Equal(t, "delivery failed", (&DeliveryError{}).Error())
+	assert.Equal(t, "unexpected status 400: Bad Request: chat not found",
+		(&DeliveryError{StatusCode: http.StatusBadRequest, Err: errors.New("Bad Request: chat not found")}).Error())
+}
+
+func TestIsRetryable(t *testing.T) {
+	rateLimited := &DeliveryError{StatusCode: http.StatusTooManyRequests, Retryable: true, RetryAfter: time.Minute}
+	unauthorized := &DeliveryError{StatusCode: http.StatusUnauthorized}
+
+	assert.False(t, IsRetryable(nil))
+	assert.True(t, IsRetryable(errors.New("connection reset")))
+	assert.False(t, IsRetryable(fmt.Errorf("format alert: %w", ErrEmptyMessage)))
+	assert.True(t, IsRetryable(fmt.Errorf("send: %w", rateLimited)))
+	assert.False(t, IsRetryable(fmt.Errorf("send: %w", unauthorized)))
+	assert.True(t, IsRetryable(errors.Join(unauthorized, rateLimited)))
+	assert.False(t, IsRetryable(errors.Join(unauthorized, unauthorized)))
+
+	assert.Equal(t, time.Minute, RetryAfter(fmt.Errorf("send: %w", errors.Join(unauthorized, rateLimited))))
+	assert.Equal(t, time.Duration(0), RetryAfter(errors.New("connection reset")))
+}
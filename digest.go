@@ -0,0 +1,280 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DigestOptions are options for the digest created by NewDigest.
+type DigestOptions struct {
+	// Schedule of the reports, e.g. ParseCron("0 9 * * *", loc) for every morning. Required.
+	Schedule Schedule
+	// TopMessages is the number of the most recurring messages listed in the report. If zero, 5 is used.
+	TopMessages int
+	// MaxMessages is the number of distinct messages tracked per period. If zero, 1000 is used.
+	MaxMessages int
+	// Severity of the report alert. If zero, SeverityInfo is used.
+	Severity Severity
+	// ErrorHandler is called when the report sent by Run could not be delivered. If nil, errors are ignored.
+	ErrorHandler func(err error)
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+}
+
+const (
+	defaultDigestTopMessages = 5
+	defaultDigestMaxMessages = 1000
+	digestUnknownApp         = "unknown"
+	// maxDigestMessageLength limits the length of the messages listed in the report.
+	maxDigestMessageLength = 200
+)
+
+// digestMessage holds statistics of a recurring message.
+type digestMessage struct {
+	message   string
+	severity  Severity
+	count     int
+	firstSeen time.Time
+	lastSeen  time.Time
+}
+
+// Digest is a notifier that records alerts and periodically sends their summary.
+type Digest struct {
+	next Notifier
+	opts DigestOptions
+
+	mu         sync.Mutex
+	start      time.Time
+	total      int
+	bySeverity map[Severity]int
+	byApp      map[string]int
+	messages   map[string]*digestMessage
+}
+
+// NewDigest returns a new digest that records every alert sent to it and sends a summary to the next
+// notifier on schedule: counts per severity and per app, the top recurring messages and their
+// first and last seen times. Alerts are not forwarded, combine the digest with other notifiers
+// using NewMultiNotifier to deliver them as well.
+//
+// Run should be started to send the reports.
+func NewDigest(next Notifier, opts *DigestOptions) (*Digest, error) {
+	if next == nil {
+		return nil, fmt.Errorf("notifier is nil")
+	}
+
+	if opts == nil || opts.Schedule == nil {
+		return nil, fmt.Errorf("digest schedule is nil")
+	}
+
+	d := &Digest{
+		next: next,
+		opts: *opts,
+	}
+
+	if d.opts.TopMessages <= 0 {
+		d.opts.TopMessages = defaultDigestTopMessages
+	}
+
+	if d.opts.MaxMessages <= 0 {
+		d.opts.MaxMessages = defaultDigestMaxMessages
+	}
+
+	if d.opts.Severity == severityUnknown {
+		d.opts.Severity = SeverityInfo
+	}
+
+	if d.opts.Now == nil {
+		d.opts.Now = time.Now
+	}
+
+	d.reset(d.opts.Now())
+
+	return d, nil
+}
+
+// Kind returns the notifier kind.
+func (d *Digest) Kind() string {
+	return fmt.Sprintf("digest[%s]", d.next.Kind())
+}
+
+// Alert records the alert for the next report.
+func (d *Digest) Alert(ctx context.Context, severity Severity, message string) error {
+	if err := validateAlert(severity, message); err != nil {
+		return err
+	}
+
+	app := digestUnknownApp
+
+	if m, ok := MetadataFromContext(ctx); ok && m.AppName != "" {
+		app = m.AppName
+	}
+
+	now := d.opts.Now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.total++
+	d.bySeverity[severity]++
+	d.byApp[app]++
+
+	msg, ok := d.messages[message]
+	if !ok {
+		if len(d.messages) >= d.opts.MaxMessages {
+			return nil
+		}
+
+		msg = &digestMessage{
+			message:   message,
+			firstSeen: now,
+		}
+
+		d.messages[message] = msg
+	}
+
+	msg.count++
+	msg.lastSeen = now
+	msg.severity = max(msg.severity, severity)
+
+	return nil
+}
+
+// Run sends the reports on schedule until ctx is canceled.
+func (d *Digest) Run(ctx context.Context) error {
+	for {
+		now := d.opts.Now()
+
+		next := d.opts.Schedule.Next(now)
+		if next.IsZero() {
+			return fmt.Errorf("digest schedule has no next activation")
+		}
+
+		timer := time.NewTimer(next.Sub(now))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return ctx.Err()
+		case <-timer.C:
+			if err := d.Send(ctx); err != nil && d.opts.ErrorHandler != nil {
+				d.opts.ErrorHandler(err)
+			}
+		}
+	}
+}
+
+// Send sends the report for the current period immediately and starts a new period.
+func (d *Digest) Send(ctx context.Context) error {
+	now := d.opts.Now()
+
+	d.mu.Lock()
+	report := d.report(now)
+	d.reset(now)
+	d.mu.Unlock()
+
+	if err := d.next.Alert(ctx, d.opts.Severity, report); err != nil {
+		return fmt.Errorf("send digest: %w", err)
+	}
+
+	return nil
+}
+
+// reset starts a new period. Must be called with mu held.
+func (d *Digest) reset(now time.Time) {
+	d.start = now
+	d.total = 0
+	d.bySeverity = make(map[Severity]int)
+	d.byApp = make(map[string]int)
+	d.messages = make(map[string]*digestMessage)
+}
+
+// report renders the summary of the current period. Must be called with mu held.
+func (d *Digest) report(now time.Time) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Digest %s - %s\n", d.start.Format(time.DateTime), now.Format(time.DateTime)))
+	sb.WriteString("Total alerts: " + strconv.Itoa(d.total))
+
+	if d.total == 0 {
+		return sb.String()
+	}
+
+	severities := make([]Severity, 0, len(d.bySeverity))
+
+	for s := range d.bySeverity {
+		severities = append(severities, s)
+	}
+
+	// The most severe first.
+	sort.Slice(severities, func(i, j int) bool {
+		return severities[i] > severities[j]
+	})
+
+	counts := make([]string, 0, len(severities))
+
+	for _, s := range severities {
+		counts = append(counts, fmt.Sprintf("%s %d", s, d.bySeverity[s]))
+	}
+
+	sb.WriteString("\nBy severity: " + strings.Join(counts, ", "))
+
+	apps := make([]string, 0, len(d.byApp))
+
+	for app := range d.byApp {
+		apps = append(apps, app)
+	}
+
+	sort.Slice(apps, func(i, j int) bool {
+		if d.byApp[apps[i]] != d.byApp[apps[j]] {
+			return d.byApp[apps[i]] > d.byApp[apps[j]]
+		}
+
+		return apps[i] < apps[j]
+	})
+
+	counts = counts[:0]
+
+	for _, app := range apps {
+		counts = append(counts, fmt.Sprintf("%s %d", app, d.byApp[app]))
+	}
+
+	sb.WriteString("\nBy app: " + strings.Join(counts, ", "))
+
+	messages := make([]*digestMessage, 0, len(d.messages))
+
+	for _, m := range d.messages {
+		messages = append(messages, m)
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		if messages[i].count != messages[j].count {
+			return messages[i].count > messages[j].count
+		}
+
+		if !messages[i].firstSeen.Equal(messages[j].firstSeen) {
+			return messages[i].firstSeen.Before(messages[j].firstSeen)
+		}
+
+		return messages[i].message < messages[j].message
+	})
+
+	if len(messages) > d.opts.TopMessages {
+		messages = messages[:d.opts.TopMessages]
+	}
+
+	sb.WriteString("\nTop messages:")
+
+	for i, m := range messages {
+		sb.WriteString(fmt.Sprintf("\n%d. [%s] %s - %d times, first seen %s, last seen %s",
+			i+1, m.severity, truncate(strings.Join(strings.Fields(m.message), " "), maxDigestMessageLength), m.count,
+			m.firstSeen.Format(time.DateTime), m.lastSeen.Format(time.DateTime)))
+	}
+
+	return sb.String()
+}
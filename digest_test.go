@@ -0,0 +1,107 @@
+package notifier_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/notifier"
+)
+
+// scheduleFunc adapts a function to notifier.Schedule.
+type scheduleFunc func(t time.Time) time.Time
+
+func (f scheduleFunc) Next(t time.Time) time.Time {
+	return f(t)
+}
+
+func TestDigest(t *testing.T) {
+	n, alerts := newRecordingNotifier()
+
+	now := time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+
+	schedule, err := notifier.ParseCron("0 9 * * *", nil)
+	require.NoError(t, err)
+
+	d, err := notifier.NewDigest(n, &notifier.DigestOptions{
+		Schedule:    schedule,
+		TopMessages: 2,
+		Now:         func() time.Time { return now },
+	})
+	require.NoError(t, err)
+
+	api := notifier.ContextWithMetadata(context.Background(), notifier.Metadata{AppName: "api"})
+	worker := notifier.ContextWithMetadata(context.Background(), notifier.Metadata{AppName: "worker"})
+
+	for _, a := range []struct {
+		ctx      context.Context
+		severity notifier.Severity
+		message  string
+	}{
+		{ctx: api, severity: notifier.SeverityWarning, message: "db is slow"},
+		{ctx: api, severity: notifier.SeverityCritical, message: "db is down"},
+		{ctx: worker, severity: notifier.SeverityWarning, message: "db is slow"},
+		{ctx: context.Background(), severity: notifier.SeverityInfo, message: "deployed"},
+		{ctx: api, severity: notifier.SeverityWarning, message: "db is slow"},
+	} {
+		now = now.Add(time.Hour)
+
+		require.NoError(t, d.Alert(a.ctx, a.severity, a.message))
+	}
+
+	assert.Empty(t, alerts)
+
+	now = time.Date(2024, time.January, 2, 9, 0, 0, 0, time.UTC)
+
+	require.NoError(t, d.Send(context.Background()))
+
+	report := waitAlert(t, alerts)
+	assert.Equal(t, notifier.SeverityInfo, report.severity)
+	assert.Equal(t, "Digest 2024-01-01 09:00:00 - 2024-01-02 09:00:00\n"+
+		"Total alerts: 5\n"+
+		"By severity: CRITICAL 1, WARNING 3, INFO 1\n"+
+		"By app: api 3, unknown 1, worker 1\n"+
+		"Top messages:\n"+
+		"1. [WARNING] db is slow - 3 times, first seen 2024-01-01 10:00:00, last seen 2024-01-01 14:00:00\n"+
+		"2. [CRITICAL] db is down - 1 times, first seen 2024-01-01 11:00:00, last seen 2024-01-01 11:00:00",
+		report.message)
+
+	// The new period is empty.
+	require.NoError(t, d.Send(context.Background()))
+	assert.Equal(t, "Digest 2024-01-02 09:00:00 - 2024-01-02 09:00:00\nTotal alerts: 0", waitAlert(t, alerts).message)
+
+	require.ErrorIs(t, d.Alert(api, notifier.SeverityWarning, ""), notifier.ErrEmptyMessage)
+}
+
+func TestDigest_Run(t *testing.T) {
+	n, alerts := newRecordingNotifier()
+
+	d, err := notifier.NewDigest(n, &notifier.DigestOptions{
+		Schedule: scheduleFunc(func(t time.Time) time.Time {
+			return t.Add(10 * time.Millisecond)
+		}),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, d.Alert(context.Background(), notifier.SeverityWarning, "db is slow"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error)
+
+	go func() {
+		done <- d.Run(ctx)
+	}()
+
+	assert.Contains(t, waitAlert(t, alerts).message, "Total alerts: 1")
+
+	cancel()
+
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	_, err = notifier.NewDigest(n, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,188 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Kubernetes downward API environment variables.
+// See https://kubernetes.io/docs/concepts/workloads/pods/downward-api/.
+const (
+	envPodName      = "POD_NAME"
+	envPodNamespace = "POD_NAMESPACE"
+	envNodeName     = "NODE_NAME"
+)
+
+// runtimeSource provides the environment information, replaced in tests.
+type runtimeSource struct {
+	hostname  func() (string, error)
+	getenv    func(key string) string
+	readFile  func(name string) ([]byte, error)
+	buildInfo func() (*debug.BuildInfo, bool)
+	pid       func() int
+}
+
+var defaultRuntimeSource = runtimeSource{
+	hostname:  os.Hostname,
+	getenv:    os.Getenv,
+	readFile:  os.ReadFile,
+	buildInfo: debug.ReadBuildInfo,
+	pid:       os.Getpid,
+}
+
+var (
+	runtimeMetadataOnce sync.Once
+	runtimeMetadata     Metadata
+)
+
+// RuntimeMetadata returns the metadata collected from the environment:
+//
+//   - AppName: the last element of the main package path;
+//   - InstanceName: the Kubernetes pod name (POD_NAME) or the hostname;
+//   - Commit and BuildDate: the VCS revision and time embedded by the Go toolchain;
+//   - Extra: pid, go_version, module_version, vcs_modified, k8s_namespace (POD_NAMESPACE),
+//     k8s_node (NODE_NAME) and container_id parsed from cgroups.
+//
+// The metadata is collected once and cached, a copy is returned.
+func RuntimeMetadata() Metadata {
+	runtimeMetadataOnce.Do(func() {
+		runtimeMetadata = defaultRuntimeSource.metadata()
+	})
+
+	m := runtimeMetadata
+	m.Extra = maps.Clone(runtimeMetadata.Extra)
+	m.Fields = slices.Clone(runtimeMetadata.Fields)
+	m.Labels = maps.Clone(runtimeMetadata.Labels)
+
+	return m
+}
+
+// EnrichMetadata returns the metadata with the empty fields filled from RuntimeMetadata.
+func EnrichMetadata(m Metadata) Metadata {
+	return mergeMetadata(RuntimeMetadata(), m)
+}
+
+// runtimeEnricher adds RuntimeMetadata to every alert.
+type runtimeEnricher struct {
+	next Notifier
+}
+
+// NewRuntimeEnricher returns a notifier that fills the alert metadata from RuntimeMetadata
+// before passing the alert to the next notifier. Fields set in the context take precedence.
+func NewRuntimeEnricher(next Notifier) (Notifier, error) {
+	if next == nil {
+		return nil, fmt.Errorf("notifier is nil")
+	}
+
+	return &runtimeEnricher{
+		next: next,
+	}, nil
+}
+
+// Kind returns the notifier kind.
+func (e *runtimeEnricher) Kind() string {
+	return e.next.Kind()
+}
+
+// Alert sends the alert with the enriched metadata.
+func (e *runtimeEnricher) Alert(ctx context.Context, severity Severity, message string) error {
+	var md Metadata
+
+	if m, ok := MetadataFromContext(ctx); ok {
+		md = *m
+	}
+
+	return e.next.Alert(contextWithMetadata(ctx, EnrichMetadata(md)), severity, message)
+}
+
+func (s runtimeSource) metadata() Metadata {
+	md := Metadata{
+		Extra: map[string]string{
+			"pid":        strconv.Itoa(s.pid()),
+			"go_version": runtime.Version(),
+		},
+	}
+
+	if host, err := s.hostname(); err == nil {
+		md.InstanceName = host
+	}
+
+	if pod := s.getenv(envPodName); pod != "" {
+		md.InstanceName = pod
+	}
+
+	if ns := s.getenv(envPodNamespace); ns != "" {
+		md.Extra["k8s_namespace"] = ns
+	}
+
+	if node := s.getenv(envNodeName); node != "" {
+		md.Extra["k8s_node"] = node
+	}
+
+	if id := s.containerID(); id != "" {
+		md.Extra["container_id"] = id
+	}
+
+	if bi, ok := s.buildInfo(); ok {
+		setBuildInfo(&md, bi)
+	}
+
+	return md
+}
+
+// setBuildInfo fills the metadata from the build information.
+func setBuildInfo(md *Metadata, bi *debug.BuildInfo) {
+	if bi.Path != "" {
+		md.AppName = path.Base(bi.Path)
+	}
+
+	if v := bi.Main.Version; v != "" && v != "(devel)" {
+		md.Extra["module_version"] = v
+	}
+
+	if bi.GoVersion != "" {
+		md.Extra["go_version"] = bi.GoVersion
+	}
+
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			md.Commit = s.Value
+		case "vcs.time":
+			md.BuildDate = s.Value
+		case "vcs.modified":
+			md.Extra["vcs_modified"] = s.Value
+		}
+	}
+}
+
+// containerIDRegex matches the 64 hex characters container ID in cgroup and mountinfo paths.
+var containerIDRegex = regexp.MustCompile(`(?:^|[/-])([0-9a-f]{64})(?:\.scope|/|$)`)
+
+// containerID returns the container ID parsed from cgroups (v1) or mountinfo (v2).
+func (s runtimeSource) containerID() string {
+	for _, name := range []string{"/proc/self/cgroup", "/proc/self/mountinfo"} {
+		data, err := s.readFile(name)
+		if err != nil {
+			continue
+		}
+
+		for _, field := range strings.Fields(string(data)) {
+			if m := containerIDRegex.FindStringSubmatch(field); m != nil {
+				return m[1]
+			}
+		}
+	}
+
+	return ""
+}
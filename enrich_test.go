@@ -0,0 +1,123 @@
+package notifier
+
+import (
+	"errors"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuntimeSource_metadata(t *testing.T) {
+	const containerID = "3f4c5e6d7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d"
+
+	env := map[string]string{
+		envPodName:      "api-7d9f",
+		envPodNamespace: "prod",
+	}
+
+	s := runtimeSource{
+		hostname: func() (string, error) { return "host", nil },
+		getenv:   func(key string) string { return env[key] },
+		readFile: func(name string) ([]byte, error) {
+			if name == "/proc/self/cgroup" {
+				return []byte("12:pids:/kubepods/burstable/pod1/cri-containerd-" + containerID + ".scope\n"), nil
+			}
+
+			return nil, errors.New("not found")
+		},
+		buildInfo: func() (*debug.BuildInfo, bool) {
+			return &debug.BuildInfo{
+				GoVersion: "go1.22.0",
+				Path:      "github.com/example/cmd/api",
+				Main:      debug.Module{Version: "v1.2.3"},
+				Settings: []debug.BuildSetting{
+					{Key: "vcs.revision", Value: "abc123"},
+					{Key: "vcs.time", Value: "2024-01-01T00:00:00Z"},
+					{Key: "vcs.modified", Value: "false"},
+				},
+			}, true
+		},
+		pid: func() int { return 42 },
+	}
+
+	assert.Equal(t, Metadata{
+		AppName:      "api",
+		InstanceName: "api-7d9f",
+		Commit:       "abc123",
+		BuildDate:    "2024-01-01T00:00:00Z",
+		Extra: map[string]string{
+			"pid":            "42",
+			"go_version":     "go1.22.0",
+			"module_version": "v1.2.3",
+			"vcs_modified":   "false",
+			"k8s_namespace":  "prod",
+			"container_id":   containerID,
+		},
+	}, s.metadata())
+}
+
+func TestRuntimeSource_containerID(t *testing.T) {
+	const id = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name:  "cgroup v1 docker",
+			files: map[string]string{"/proc/self/cgroup": "1:name=systemd:/docker/" + id + "\n"},
+			want:  id,
+		},
+		{
+			name: "cgroup v2 mountinfo",
+			files: map[string]string{
+				"/proc/self/cgroup":    "0::/\n",
+				"/proc/self/mountinfo": "522 517 0:45 /var/lib/docker/containers/" + id + "/hostname /etc/hostname rw\n",
+			},
+			want: id,
+		},
+		{
+			name:  "not a container",
+			files: map[string]string{"/proc/self/cgroup": "0::/user.slice/user-1000.slice\n"},
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := runtimeSource{
+				readFile: func(name string) ([]byte, error) {
+					data, ok := tt.files[name]
+					if !ok {
+						return nil, errors.New("not found")
+					}
+
+					return []byte(data), nil
+				},
+			}
+
+			assert.Equal(t, tt.want, s.containerID())
+		})
+	}
+}
+
+func TestEnrichMetadata(t *testing.T) {
+	got := EnrichMetadata(Metadata{
+		AppName: "my_app",
+		Extra:   map[string]string{"env": "test"},
+	})
+
+	assert.Equal(t, "my_app", got.AppName)
+	assert.Equal(t, "test", got.Extra["env"])
+	assert.NotEmpty(t, got.Extra["pid"])
+	assert.NotEmpty(t, got.Extra["go_version"])
+}
+
+func TestRuntimeMetadata_Copy(t *testing.T) {
+	m := RuntimeMetadata()
+	m.Extra["pid"] = "changed"
+
+	assert.NotEqual(t, "changed", RuntimeMetadata().Extra["pid"])
+}
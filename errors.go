@@ -15,4 +15,48 @@
 	ErrEmptyNotifiers = errors.New("notifiers list is empty")
 	// ErrInvalidSeverity is returned when the severity is invalid.
 	ErrInvalidSeverity = errors.New("invalid severity")
+	// ErrEmptyTwilioAccountSID is returned when the twilio account sid is empty.
+	ErrEmptyTwilioAccountSID = errors.New("twilio account sid is empty")
+	// ErrEmptyTwilioAuthToken is returned when the twilio auth token is empty.
+	ErrEmptyTwilioAuthToken = errors.New("twilio auth token is empty")
+	// ErrEmptySMSFrom is returned when the sms sender phone number is empty.
+	ErrEmptySMSFrom = errors.New("sms sender number is empty")
+	// ErrEmptySMSRecipients is returned when the sms recipients' list is empty.
+	ErrEmptySMSRecipients = errors.New("sms recipients list is empty")
+	// ErrNoTemplate is returned when the template set has no template for the alert severity.
+	ErrNoTemplate = errors.New("no template")
+	// ErrInvalidConfig is returned when the configuration is invalid.
+	ErrInvalidConfig = errors.New("invalid config")
+	// ErrUnknownConfigKind is returned when the configuration refers to an unregistered kind.
+	ErrUnknownConfigKind = errors.New("unknown kind")
+	// ErrUnknownScheme is returned when there is no factory registered for the notifier URL scheme.
+	ErrUnknownScheme = errors.New("unknown notifier url scheme")
+	// ErrInvalidSilence is returned when the silence is invalid.
+	ErrInvalidSilence = errors.New("invalid silence")
+	// ErrSilenceNotFound is returned when there is no silence with the given ID.
+	ErrSilenceNotFound = errors.New("silence not found")
+	// ErrGrouperClosed is returned when the alert is sent to the closed Grouper.
+	ErrGrouperClosed = errors.New("grouper is closed")
+	// ErrUnknownHeartbeat is returned when the beat is recorded for a heartbeat that is not watched.
+	ErrUnknownHeartbeat = errors.New("unknown heartbeat")
+	// ErrAlertNotFound is returned when there is no pending alert with the given ID.
+	ErrAlertNotFound = errors.New("alert not found")
+	// ErrNoOnCall is returned when nobody is on call at the given time.
+	ErrNoOnCall = errors.New("nobody is on call")
+	// ErrOutboxClosed is returned when the alert is sent to the closed Outbox.
+	ErrOutboxClosed = errors.New("outbox is closed")
+	// ErrTooManyPendingAlerts is returned when the alert is dropped because too many alerts are being sent.
+	ErrTooManyPendingAlerts = errors.New("too many pending alerts")
+	// ErrCircuitOpen is returned when the alert is rejected by the open circuit breaker.
+	ErrCircuitOpen = errors.New("circuit is open")
+	// ErrInvalidPolicy is returned when the delivery policy is invalid.
+	ErrInvalidPolicy = errors.New("invalid delivery policy")
+	// ErrQuorumNotReached is returned when fewer notifiers than the delivery policy requires delivered the alert.
+	ErrQuorumNotReached = errors.New("delivery quorum is not reached")
+	// ErrRateLimited is returned when the destination rejected the alert because of the rate limit.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrUnauthorized is returned when the destination rejected the credentials or the access is forbidden.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrPayloadTooLarge is returned when the destination rejected the alert as too large.
+	ErrPayloadTooLarge = errors.New("payload too large")
 )
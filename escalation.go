@@ -0,0 +1,315 @@
+package notifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// metadataAlertID is the metadata key of the alert ID used to acknowledge escalated alerts.
+const metadataAlertID = "alert_id"
+
+// EscalationTier is a step of the escalation policy.
+type EscalationTier struct {
+	// Notifier of the tier. Use NewMultiNotifier to notify several channels.
+	Notifier Notifier
+	// Delay after the previous tier is notified before this tier is notified. Ignored for the first tier.
+	Delay time.Duration
+}
+
+// EscalationOptions are options for the notifier created by NewEscalation.
+type EscalationOptions struct {
+	// MinSeverity is the lowest escalated severity. Alerts below it are sent to the first tier only.
+	// If zero, all alerts are escalated.
+	MinSeverity Severity
+	// RepeatInterval is the interval of re-sending unacknowledged alerts of RepeatSeverity or higher
+	// to the last tier. If zero, alerts are not repeated.
+	RepeatInterval time.Duration
+	// RepeatSeverity is the lowest repeated severity. If zero, SeverityCritical is used.
+	RepeatSeverity Severity
+	// ErrorHandler is called when the escalated alert could not be delivered. If nil, errors are ignored.
+	ErrorHandler func(err error)
+	// AfterFunc calls f after d and returns the function that stops the call, see time.AfterFunc.
+	// If nil, time.AfterFunc is used.
+	AfterFunc func(d time.Duration, f func()) (stop func() bool)
+}
+
+// escalatedAlert is an unacknowledged alert being escalated.
+type escalatedAlert struct {
+	ctx      context.Context
+	severity Severity
+	message  string
+	// tier is the index of the last notified tier.
+	tier    int
+	repeats int
+	// stop stops the timer of the next escalation step.
+	stop func() bool
+}
+
+// Escalation is a notifier that escalates unacknowledged alerts through tiers of notifiers.
+type Escalation struct {
+	tiers []EscalationTier
+	opts  EscalationOptions
+
+	mu     sync.Mutex
+	alerts map[string]*escalatedAlert
+}
+
+// NewEscalation returns a new notifier that sends alerts to the first tier and, unless the alert
+// is acknowledged with Ack within the next tier delay, to the next tier, and so on.
+// Unacknowledged alerts of RepeatSeverity or higher are re-sent to the last tier every RepeatInterval.
+//
+// Alerts are identified by the "alert_id" metadata field or, if it is not set, by AlertFingerprint.
+// An alert with the ID that is already being escalated is not sent again, unless the first tier
+// failed to deliver it. Once the alert reached the last tier and is not repeated, it is not tracked
+// anymore and the same alert is sent again.
+// Close should be called on shutdown to stop the pending escalations.
+func NewEscalation(tiers []EscalationTier, opts *EscalationOptions) (*Escalation, error) {
+	if len(tiers) == 0 {
+		return nil, ErrEmptyNotifiers
+	}
+
+	for i, t := range tiers {
+		if t.Notifier == nil {
+			return nil, fmt.Errorf("escalation tier %d: notifier is nil", i+1)
+		}
+
+		if i > 0 && t.Delay <= 0 {
+			return nil, fmt.Errorf("escalation tier %d: delay should be positive", i+1)
+		}
+	}
+
+	e := &Escalation{
+		tiers:  tiers,
+		alerts: make(map[string]*escalatedAlert),
+	}
+
+	if opts != nil {
+		e.opts = *opts
+	}
+
+	if e.opts.RepeatSeverity == severityUnknown {
+		e.opts.RepeatSeverity = SeverityCritical
+	}
+
+	if e.opts.AfterFunc == nil {
+		e.opts.AfterFunc = func(d time.Duration, f func()) func() bool {
+			return time.AfterFunc(d, f).Stop
+		}
+	}
+
+	return e, nil
+}
+
+// Kind returns the notifier kind.
+func (e *Escalation) Kind() string {
+	kinds := make([]string, 0, len(e.tiers))
+
+	for _, t := range e.tiers {
+		kinds = append(kinds, t.Notifier.Kind())
+	}
+
+	return fmt.Sprintf("escalation[%s]", strings.Join(kinds, ";"))
+}
+
+// Alert sends the alert to the first tier and schedules its escalation.
+func (e *Escalation) Alert(ctx context.Context, severity Severity, message string) error {
+	if err := validateAlert(severity, message); err != nil {
+		return err
+	}
+
+	id := alertID(ctx, severity, message)
+
+	e.mu.Lock()
+
+	if _, ok := e.alerts[id]; ok {
+		e.mu.Unlock()
+
+		return nil
+	}
+
+	a := &escalatedAlert{
+		ctx:      ContextWithField(context.WithoutCancel(ctx), metadataAlertID, id),
+		severity: severity,
+		message:  message,
+	}
+
+	if severity >= e.opts.MinSeverity {
+		e.alerts[id] = a
+		e.schedule(id, a)
+	}
+
+	e.mu.Unlock()
+
+	if err := e.send(a, 0, 0); err != nil {
+		// The alert is not delivered, so it stops being tracked and the retry of the caller is sent again.
+		e.mu.Lock()
+
+		if e.alerts[id] == a {
+			if a.stop != nil {
+				a.stop()
+			}
+
+			delete(e.alerts, id)
+		}
+
+		e.mu.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+// Ack acknowledges the alert by its ID and stops its escalation.
+func (e *Escalation) Ack(id string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	a, ok := e.alerts[id]
+	if !ok {
+		return fmt.Errorf("'%s': %w", id, ErrAlertNotFound)
+	}
+
+	if a.stop != nil {
+		a.stop()
+	}
+
+	delete(e.alerts, id)
+
+	return nil
+}
+
+// Pending returns the IDs of the unacknowledged alerts being escalated.
+func (e *Escalation) Pending() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ids := make([]string, 0, len(e.alerts))
+
+	for id := range e.alerts {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+// Close stops all pending escalations.
+func (e *Escalation) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for id, a := range e.alerts {
+		if a.stop != nil {
+			a.stop()
+		}
+
+		delete(e.alerts, id)
+	}
+
+	return nil
+}
+
+// schedule starts the timer of the next escalation step. If there is nothing left to escalate,
+// the alert stops being tracked. Must be called with mu held.
+func (e *Escalation) schedule(id string, a *escalatedAlert) {
+	var delay time.Duration
+
+	switch {
+	case a.tier+1 < len(e.tiers):
+		delay = e.tiers[a.tier+1].Delay
+	case e.opts.RepeatInterval > 0 && a.severity >= e.opts.RepeatSeverity:
+		delay = e.opts.RepeatInterval
+	default:
+		// The alert reached the last tier and is not repeated, nothing to wait for.
+		a.stop = nil
+
+		delete(e.alerts, id)
+
+		return
+	}
+
+	a.stop = e.opts.AfterFunc(delay, func() {
+		e.escalate(id, a)
+	})
+}
+
+// escalate notifies the next tier or repeats the alert to the last tier.
+func (e *Escalation) escalate(id string, a *escalatedAlert) {
+	e.mu.Lock()
+
+	if e.alerts[id] != a {
+		// Acknowledged meanwhile.
+		e.mu.Unlock()
+
+		return
+	}
+
+	if a.tier+1 < len(e.tiers) {
+		a.tier++
+	} else {
+		a.repeats++
+	}
+
+	tier, repeats := a.tier, a.repeats
+
+	e.schedule(id, a)
+
+	e.mu.Unlock()
+
+	if err := e.send(a, tier, repeats); err != nil && e.opts.ErrorHandler != nil {
+		e.opts.ErrorHandler(err)
+	}
+}
+
+// send sends the alert to the tier, repeats is the number of the alert repeats.
+func (e *Escalation) send(a *escalatedAlert, tier, repeats int) error {
+	ctx := a.ctx
+
+	if tier > 0 {
+		ctx = ContextWithField(ctx, "escalation_tier", strconv.Itoa(tier+1))
+	}
+
+	if repeats > 0 {
+		ctx = ContextWithField(ctx, "repeat", strconv.Itoa(repeats))
+	}
+
+	if err := e.tiers[tier].Notifier.Alert(ctx, a.severity, a.message); err != nil {
+		return fmt.Errorf("escalation tier %d: %w", tier+1, err)
+	}
+
+	return nil
+}
+
+// AlertFingerprint returns the ID of the alert without the "alert_id" metadata field:
+// a hash of its severity, message and app name.
+func AlertFingerprint(ctx context.Context, severity Severity, message string) string {
+	var app string
+
+	if m, ok := MetadataFromContext(ctx); ok {
+		app = m.AppName
+	}
+
+	sum := sha256.Sum256([]byte(app + "\x00" + severity.String() + "\x00" + message))
+
+	return hex.EncodeToString(sum[:8])
+}
+
+// alertID returns the "alert_id" metadata field, falling back to AlertFingerprint.
+func alertID(ctx context.Context, severity Severity, message string) string {
+	if m, ok := MetadataFromContext(ctx); ok {
+		if id := fieldsMap(m.fields())[metadataAlertID]; id != "" {
+			return id
+		}
+	}
+
+	return AlertFingerprint(ctx, severity, message)
+}
@@ -0,0 +1,230 @@
+package notifier_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/notifier"
+)
+
+// fieldValue returns the value of the metadata field by its key.
+func fieldValue(md notifier.Metadata, key string) string {
+	for _, f := range md.Fields {
+		if f.Key == key {
+			return f.Value
+		}
+	}
+
+	return ""
+}
+
+// manualTimers runs the escalation timers on demand.
+type manualTimers struct {
+	mu     sync.Mutex
+	timers []*manualTimer
+}
+
+type manualTimer struct {
+	delay   time.Duration
+	f       func()
+	stopped bool
+}
+
+func (m *manualTimers) afterFunc(d time.Duration, f func()) func() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t := &manualTimer{delay: d, f: f}
+	m.timers = append(m.timers, t)
+
+	return func() bool {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		stopped := t.stopped
+		t.stopped = true
+
+		return !stopped
+	}
+}
+
+// fire runs the pending timers and returns their delays.
+func (m *manualTimers) fire() []time.Duration {
+	m.mu.Lock()
+
+	var pending []*manualTimer
+
+	for _, t := range m.timers {
+		if !t.stopped {
+			t.stopped = true
+			pending = append(pending, t)
+		}
+	}
+
+	m.timers = nil
+
+	m.mu.Unlock()
+
+	delays := make([]time.Duration, 0, len(pending))
+
+	for _, t := range pending {
+		delays = append(delays, t.delay)
+		t.f()
+	}
+
+	return delays
+}
+
+func TestEscalation(t *testing.T) {
+	tier1, alerts1 := newRecordingNotifier()
+	tier2, alerts2 := newRecordingNotifier()
+
+	var timers manualTimers
+
+	e, err := notifier.NewEscalation([]notifier.EscalationTier{
+		{Notifier: tier1},
+		{Notifier: tier2, Delay: time.Minute},
+	}, &notifier.EscalationOptions{
+		RepeatInterval: time.Hour,
+		AfterFunc:      timers.afterFunc,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, e.Close())
+	})
+
+	ctx := notifier.ContextWithField(context.Background(), "alert_id", "db-down")
+
+	require.NoError(t, e.Alert(ctx, notifier.SeverityCritical, "database is down"))
+
+	first := waitAlert(t, alerts1)
+	assert.Equal(t, "database is down", first.message)
+	assert.Equal(t, "db-down", fieldValue(first.metadata, "alert_id"))
+
+	// The same alert is not sent again while it is escalated.
+	require.NoError(t, e.Alert(ctx, notifier.SeverityCritical, "database is down"))
+	assert.Equal(t, []string{"db-down"}, e.Pending())
+	assert.Empty(t, alerts1)
+
+	assert.Equal(t, []time.Duration{time.Minute}, timers.fire())
+
+	escalated := waitAlert(t, alerts2)
+	assert.Equal(t, "2", fieldValue(escalated.metadata, "escalation_tier"))
+
+	assert.Equal(t, []time.Duration{time.Hour}, timers.fire())
+
+	repeated := waitAlert(t, alerts2)
+	assert.Equal(t, "1", fieldValue(repeated.metadata, "repeat"))
+
+	require.NoError(t, e.Ack("db-down"))
+	require.ErrorIs(t, e.Ack("db-down"), notifier.ErrAlertNotFound)
+	assert.Empty(t, e.Pending())
+
+	// The acknowledged alert is not repeated.
+	assert.Empty(t, timers.fire())
+	assert.Empty(t, alerts2)
+	assert.Empty(t, alerts1)
+}
+
+func TestEscalation_Ack(t *testing.T) {
+	tier1, alerts1 := newRecordingNotifier()
+	tier2, alerts2 := newRecordingNotifier()
+
+	var timers manualTimers
+
+	e, err := notifier.NewEscalation([]notifier.EscalationTier{
+		{Notifier: tier1},
+		{Notifier: tier2, Delay: time.Minute},
+	}, &notifier.EscalationOptions{
+		MinSeverity: notifier.SeverityWarning,
+		AfterFunc:   timers.afterFunc,
+	})
+	require.NoError(t, err)
+
+	ctx := notifier.ContextWithMetadata(context.Background(), notifier.Metadata{AppName: "api"})
+
+	require.NoError(t, e.Alert(ctx, notifier.SeverityWarning, "latency is high"))
+	waitAlert(t, alerts1)
+
+	require.NoError(t, e.Ack(notifier.AlertFingerprint(ctx, notifier.SeverityWarning, "latency is high")))
+
+	// Alerts below MinSeverity are not escalated.
+	require.NoError(t, e.Alert(ctx, notifier.SeverityInfo, "deployed"))
+	waitAlert(t, alerts1)
+	assert.Empty(t, e.Pending())
+
+	assert.Empty(t, timers.fire())
+	assert.Empty(t, alerts2)
+
+	require.ErrorIs(t, e.Alert(ctx, notifier.SeverityWarning, ""), notifier.ErrEmptyMessage)
+
+	_, err = notifier.NewEscalation(nil, nil)
+	require.ErrorIs(t, err, notifier.ErrEmptyNotifiers)
+
+	_, err = notifier.NewEscalation([]notifier.EscalationTier{{Notifier: tier1}, {Notifier: tier2}}, nil)
+	require.Error(t, err)
+}
+
+func TestEscalation_NothingToEscalate(t *testing.T) {
+	tier, alerts := newRecordingNotifier()
+
+	e, err := notifier.NewEscalation([]notifier.EscalationTier{
+		{Notifier: tier},
+	}, nil)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, e.Close())
+	})
+
+	ctx := notifier.ContextWithField(context.Background(), "alert_id", "disk-full")
+
+	for range 2 {
+		require.NoError(t, e.Alert(ctx, notifier.SeverityCritical, "disk is full"))
+		assert.Equal(t, "disk is full", waitAlert(t, alerts).message)
+		assert.Empty(t, e.Pending())
+	}
+}
+
+func TestEscalation_FirstTierFailed(t *testing.T) {
+	down := true
+
+	tier1 := notifierFunc(func(context.Context, notifier.Severity, string) error {
+		if down {
+			return errors.New("telegram is down")
+		}
+
+		return nil
+	})
+
+	tier2, alerts2 := newRecordingNotifier()
+
+	e, err := notifier.NewEscalation([]notifier.EscalationTier{
+		{Notifier: tier1},
+		{Notifier: tier2, Delay: time.Hour},
+	}, nil)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, e.Close())
+	})
+
+	ctx := notifier.ContextWithField(context.Background(), "alert_id", "db-down")
+
+	require.EqualError(t, e.Alert(ctx, notifier.SeverityCritical, "database is down"), "escalation tier 1: telegram is down")
+	assert.Empty(t, e.Pending())
+
+	// The retry is sent again and escalated.
+	down = false
+
+	require.NoError(t, e.Alert(ctx, notifier.SeverityCritical, "database is down"))
+	assert.Equal(t, []string{"db-down"}, e.Pending())
+	assert.Empty(t, alerts2)
+}
@@ -0,0 +1,95 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FailoverOptions are options for the notifier created by NewFailoverWithOptions.
+type FailoverOptions struct {
+	// OnDelivered is called with the alert context and the kind of the notifier that delivered the alert,
+	// before Alert returns. It must be safe for concurrent use.
+	OnDelivered func(ctx context.Context, kind string)
+}
+
+// Failover is a notifier that sends alerts to the first notifier that delivers them.
+type Failover struct {
+	notifiers []Notifier
+	opts      FailoverOptions
+}
+
+// NewFailover returns a new notifier that tries the primary notifier first and then the fallbacks in order,
+// stopping at the first successful delivery. Unlike NewMultiNotifier, fallbacks are notified only
+// when all previous notifiers failed.
+func NewFailover(primary Notifier, fallbacks ...Notifier) (*Failover, error) {
+	return NewFailoverWithOptions(nil, primary, fallbacks...)
+}
+
+// NewFailoverWithOptions returns a new failover notifier with the options,
+// e.g. to record which notifier delivered every alert.
+func NewFailoverWithOptions(opts *FailoverOptions, primary Notifier, fallbacks ...Notifier) (*Failover, error) {
+	if primary == nil {
+		return nil, ErrEmptyNotifiers
+	}
+
+	notifiers := append([]Notifier{primary}, fallbacks...)
+
+	for i, n := range notifiers {
+		if n == nil {
+			return nil, fmt.Errorf("failover notifier %d is nil", i+1)
+		}
+	}
+
+	f := &Failover{
+		notifiers: notifiers,
+	}
+
+	if opts != nil {
+		f.opts = *opts
+	}
+
+	return f, nil
+}
+
+// Kind returns the notifier kind.
+func (f *Failover) Kind() string {
+	kinds := make([]string, 0, len(f.notifiers))
+
+	for _, n := range f.notifiers {
+		kinds = append(kinds, n.Kind())
+	}
+
+	return fmt.Sprintf("failover[%s]", strings.Join(kinds, ";"))
+}
+
+// Alert sends the alert to the notifiers in order until one of them succeeds.
+// If all notifiers fail, the joined errors are returned.
+func (f *Failover) Alert(ctx context.Context, severity Severity, message string) error {
+	if err := validateAlert(severity, message); err != nil {
+		return fmt.Errorf("send alert to '%s': %w", f.Kind(), err)
+	}
+
+	var errs error
+
+	for _, n := range f.notifiers {
+		err := n.Alert(ctx, severity, message)
+		if err == nil {
+			if f.opts.OnDelivered != nil {
+				f.opts.OnDelivered(ctx, n.Kind())
+			}
+
+			return nil
+		}
+
+		if errors.Is(err, ErrEmptyMessage) || errors.Is(err, ErrInvalidSeverity) {
+			// The alert is invalid, the fallbacks would reject it as well.
+			return fmt.Errorf("send alert to '%s': %w", f.Kind(), err)
+		}
+
+		errs = errors.Join(errs, fmt.Errorf("send alert to '%s': %w", n.Kind(), err))
+	}
+
+	return errs
+}
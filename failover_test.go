@@ -0,0 +1,150 @@
+package notifier_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/notifier"
+)
+
+func TestFailover(t *testing.T) {
+	var primaryUp bool
+
+	primary := notifierFunc(func(context.Context, notifier.Severity, string) error {
+		if !primaryUp {
+			return errors.New("telegram is down")
+		}
+
+		return nil
+	})
+
+	var (
+		email     bytes.Buffer
+		delivered string
+	)
+
+	f, err := notifier.NewFailoverWithOptions(&notifier.FailoverOptions{
+		OnDelivered: func(_ context.Context, kind string) {
+			delivered = kind
+		},
+	}, primary, newTestNotifier(t, &email, "email"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "failover[func;iowriter: email]", f.Kind())
+
+	ctx := context.Background()
+
+	require.NoError(t, f.Alert(ctx, notifier.SeverityCritical, "database is down"))
+	assert.Contains(t, email.String(), "database is down")
+	assert.Equal(t, "iowriter: email", delivered)
+
+	email.Reset()
+
+	primaryUp = true
+
+	require.NoError(t, f.Alert(ctx, notifier.SeverityCritical, "database is down"))
+	assert.Empty(t, email.String())
+	assert.Equal(t, "func", delivered)
+
+	require.ErrorIs(t, f.Alert(ctx, notifier.SeverityCritical, ""), notifier.ErrEmptyMessage)
+}
+
+func TestFailover_OnDeliveredConcurrent(t *testing.T) {
+	// Telegram rejects the alerts with odd IDs, so they are delivered by email.
+	telegram := notifierFunc(func(ctx context.Context, _ notifier.Severity, _ string) error {
+		m, _ := notifier.MetadataFromContext(ctx)
+
+		if id, _ := strconv.Atoi(fieldValue(*m, "alert_id")); id%2 == 1 {
+			return errors.New("telegram is down")
+		}
+
+		return nil
+	})
+
+	email := notifier.Notifier(&namedNotifier{kind: "email"})
+
+	var (
+		mu        sync.Mutex
+		delivered = make(map[string]string)
+	)
+
+	f, err := notifier.NewFailoverWithOptions(&notifier.FailoverOptions{
+		OnDelivered: func(ctx context.Context, kind string) {
+			m, _ := notifier.MetadataFromContext(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			delivered[fieldValue(*m, "alert_id")] = kind
+		},
+	}, telegram, email)
+	require.NoError(t, err)
+
+	const alerts = 50
+
+	var wg sync.WaitGroup
+
+	for i := range alerts {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			ctx := notifier.ContextWithField(context.Background(), "alert_id", strconv.Itoa(i))
+
+			assert.NoError(t, f.Alert(ctx, notifier.SeverityCritical, "database is down"))
+		}()
+	}
+
+	wg.Wait()
+
+	require.Len(t, delivered, alerts)
+
+	for id, kind := range delivered {
+		n, err := strconv.Atoi(id)
+		require.NoError(t, err)
+
+		if n%2 == 1 {
+			assert.Equal(t, "email", kind, id)
+		} else {
+			assert.Equal(t, "func", kind, id)
+		}
+	}
+}
+
+// namedNotifier is a notifier of the given kind that accepts all alerts.
+type namedNotifier struct {
+	kind string
+}
+
+func (n *namedNotifier) Alert(context.Context, notifier.Severity, string) error {
+	return nil
+}
+
+func (n *namedNotifier) Kind() string {
+	return n.kind
+}
+
+func TestFailover_AllFailed(t *testing.T) {
+	down := func(name string) notifier.Notifier {
+		return notifierFunc(func(context.Context, notifier.Severity, string) error {
+			return errors.New(name + " is down")
+		})
+	}
+
+	f, err := notifier.NewFailover(down("telegram"), down("email"))
+	require.NoError(t, err)
+
+	err = f.Alert(context.Background(), notifier.SeverityCritical, "database is down")
+	require.EqualError(t, err, "send alert to 'func': telegram is down\nsend alert to 'func': email is down")
+
+	_, err = notifier.NewFailover(nil)
+	require.ErrorIs(t, err, notifier.ErrEmptyNotifiers)
+}
@@ -1,76 +1,82 @@
 package notifier
 
 import (
-	"bytes"
 	"context"
 	_ "embed"
 	"fmt"
-	"text/template"
-
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"time"
 )
 
-// alertData holds the alert information that will be sent in the Telegram message.
-type alertData struct {
-	Message  string
+// AlertData holds the alert information available in alert templates.
+type AlertData struct {
+	// Message is the alert message, escaped for Telegram HTML.
+	Message string
+	// Severity is the alert severity.
 	Severity Severity
+	// Metadata holds the non-empty metadata fields from the context by key, escaped for Telegram HTML.
 	Metadata map[string]string
+	// Fields holds the non-empty metadata fields from the context in the rendering order,
+	// escaped for Telegram HTML.
+	Fields []MetadataField
+	// Time is the time when the alert was formatted.
+	Time time.Time
 }
 
 var (
 	//go:embed format.gohtml
 	alertFormat string
 
-	tplAlert = template.Must(
-		template.New("alert").
-			Funcs(template.FuncMap{"severityEmoji": severityEmoji}).
-			Parse(alertFormat),
-	)
+	defaultTemplates = func() *Templates {
+		t, err := NewTemplates(alertFormat)
+		if err != nil {
+			panic(err)
+		}
+
+		return t
+	}()
 )
 
 const (
-	emojiInfo     = "ℹ️"
-	emojiWarning  = "⚠️"
-	emojiCritical = "🚨"
+	emojiDebug     = "🐞"
+	emojiInfo      = "ℹ️"
+	emojiWarning   = "⚠️"
+	emojiError     = "❌"
+	emojiCritical  = "🚨"
+	emojiEmergency = "🆘"
 )
 
+// severityToEmoji is guarded by severityMu, custom levels are added with RegisterSeverity.
 var severityToEmoji = map[Severity]string{
-	SeverityInfo:     emojiInfo,
-	SeverityWarning:  emojiWarning,
-	SeverityCritical: emojiCritical,
+	SeverityDebug:     emojiDebug,
+	SeverityInfo:      emojiInfo,
+	SeverityWarning:   emojiWarning,
+	SeverityError:     emojiError,
+	SeverityCritical:  emojiCritical,
+	SeverityEmergency: emojiEmergency,
 }
 
 // severityEmoji returns the emoji for the given severity.
 func severityEmoji(severity Severity) string {
+	severityMu.RLock()
+	defer severityMu.RUnlock()
+
 	return severityToEmoji[severity]
 }
 
-// formatAlert formats the alert message using the Golang template.
-func formatAlert(ctx context.Context, severity Severity, message string) (string, error) {
+// validateAlert checks that the alert could be sent.
+func validateAlert(severity Severity, message string) error {
 	if message == "" {
-		return "", ErrEmptyMessage
+		return ErrEmptyMessage
 	}
 
 	if !severity.Valid() {
-		return "", fmt.Errorf("'%s', should be one of '%v': %w", severity, allowedSeverities, ErrInvalidSeverity)
-	}
-
-	var buf bytes.Buffer
-
-	ad := alertData{
-		Message:  tgbotapi.EscapeText(tgbotapi.ModeHTML, message),
-		Severity: severity,
-		Metadata: nil,
-	}
-
-	m, ok := MetadataFromContext(ctx)
-	if ok {
-		ad.Metadata = m.toMap()
+		return fmt.Errorf("'%s', should be one of '%v': %w", severity, allowedSeverities(), ErrInvalidSeverity)
 	}
 
-	if err := tplAlert.Execute(&buf, ad); err != nil {
-		return "", err
-	}
+	return nil
+}
 
-	return buf.String(), nil
+// formatAlert formats the alert message using the default template.
+func formatAlert(ctx context.Context, severity Severity, message string) (string, error) {
+	return defaultTemplates.Format(ctx, severity, message)
 }
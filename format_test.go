@@ -87,6 +87,30 @@ type args struct {
 			wantPath: filepath.Join("testdata", "Test_formatAlert_metadata_with_missed_app_name.golden"),
 			wantErr:  require.NoError,
 		},
+		{
+			name: "grouped metadata with labels, with context",
+			metadata: &Metadata{
+				AppName: "test_app",
+				Extra: map[string]string{
+					"env": "prod",
+				},
+				Fields: []MetadataField{
+					{Key: "method", Value: "GET", Group: "Request"},
+					{Key: "user", Value: "john"},
+					{Key: "status", Value: "500", Label: "Status", Group: "Request"},
+				},
+				Labels: map[string]string{
+					"app_name": "Application",
+				},
+			},
+			ctx: ctx,
+			args: args{
+				message:  "test message",
+				severity: SeverityInfo,
+			},
+			wantPath: filepath.Join("testdata", "Test_formatAlert_with_grouped_metadata.golden"),
+			wantErr:  require.NoError,
+		},
 		{
 			name: "metadata with empty fields, with context",
 			metadata: &Metadata{
@@ -0,0 +1,203 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Formatter renders alerts into messages of a specific markup.
+// Formatters validate the alert and escape the message and metadata for their markup.
+type Formatter interface {
+	// Format renders the alert with the metadata from ctx.
+	Format(ctx context.Context, severity Severity, message string) (string, error)
+}
+
+// parseModer is implemented by formatters which output should be sent with a Telegram parse mode.
+type parseModer interface {
+	// ParseMode returns the Telegram parse mode of the formatted message.
+	ParseMode() string
+}
+
+// formatterParseMode returns the Telegram parse mode for the formatter output.
+func formatterParseMode(f Formatter) string {
+	if pm, ok := f.(parseModer); ok {
+		return pm.ParseMode()
+	}
+
+	return ""
+}
+
+// HTMLFormatter returns the formatter that renders alerts in Telegram HTML, it's the DefaultTemplates.
+func HTMLFormatter() Formatter {
+	return DefaultTemplates()
+}
+
+// MarkdownV2Formatter returns the formatter that renders alerts in Telegram MarkdownV2.
+func MarkdownV2Formatter() Formatter {
+	return markdownV2Formatter
+}
+
+// SlackFormatter returns the formatter that renders alerts in Slack mrkdwn.
+func SlackFormatter() Formatter {
+	return slackFormatter
+}
+
+// PlainFormatter returns the formatter that renders alerts as plain text, suitable for logs.
+func PlainFormatter() Formatter {
+	return plainFormatter
+}
+
+// JSONFormatter returns the formatter that renders alerts as a JSON object:
+//
+//	{"severity":"WARNING","message":"text","metadata":{"app_name":"app"},"time":"2020-01-01T00:00:00Z"}
+func JSONFormatter() Formatter {
+	return jsonFormatter{}
+}
+
+var (
+	markdownV2Formatter = textFormatter{
+		escape:    escapeMarkdownV2,
+		bold:      func(s string) string { return "*" + s + "*" },
+		parseMode: tgbotapi.ModeMarkdownV2,
+	}
+
+	slackFormatter = textFormatter{
+		escape:    escapeSlack,
+		bold:      func(s string) string { return "*" + s + "*" },
+		parseMode: "",
+	}
+
+	plainFormatter = textFormatter{
+		escape:    func(s string) string { return s },
+		bold:      func(s string) string { return s },
+		parseMode: "",
+	}
+)
+
+// markdownV2Replacer escapes all characters reserved by Telegram MarkdownV2, including the backslash.
+var markdownV2Replacer = strings.NewReplacer(
+	"\\", "\\\\",
+	"_", "\\_", "*", "\\*", "[", "\\[", "]", "\\]", "(", "\\(", ")", "\\)",
+	"~", "\\~", "`", "\\`", ">", "\\>", "#", "\\#", "+", "\\+", "-", "\\-",
+	"=", "\\=", "|", "\\|", "{", "\\{", "}", "\\}", ".", "\\.", "!", "\\!",
+)
+
+func escapeMarkdownV2(s string) string {
+	return markdownV2Replacer.Replace(s)
+}
+
+// slackReplacer escapes control characters of Slack mrkdwn.
+var slackReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func escapeSlack(s string) string {
+	return slackReplacer.Replace(s)
+}
+
+// textFormatter renders alerts in the layout of the default template with the markup specific escaping.
+type textFormatter struct {
+	escape    func(string) string
+	bold      func(string) string
+	parseMode string
+}
+
+// ParseMode returns the Telegram parse mode of the formatted message.
+func (f textFormatter) ParseMode() string {
+	return f.parseMode
+}
+
+// Format renders the alert.
+func (f textFormatter) Format(ctx context.Context, severity Severity, message string) (string, error) {
+	if err := validateAlert(severity, message); err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(f.bold(f.escape(severityEmoji(severity)+" Severity:")) + " " + f.escape(severity.String()) + "\n")
+	sb.WriteString(f.bold(f.escape("Alert Message:")) + " " + f.escape(message))
+
+	m, ok := MetadataFromContext(ctx)
+	if !ok {
+		return sb.String(), nil
+	}
+
+	fields := m.fields()
+	if len(fields) == 0 {
+		return sb.String(), nil
+	}
+
+	sb.WriteString("\n" + f.bold(f.escape("Meta:")))
+
+	var group string
+
+	for _, field := range fields {
+		if field.Group != group {
+			group = field.Group
+
+			sb.WriteString("\n" + f.bold(f.escape(group+":")))
+		}
+
+		sb.WriteString("\n\t" + f.escape("• "+field.Name()+": "+field.Value))
+	}
+
+	return sb.String(), nil
+}
+
+// jsonAlert is the JSON representation of the alert.
+type jsonAlert struct {
+	Severity string            `json:"severity"`
+	Message  string            `json:"message"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Time     time.Time         `json:"time"`
+}
+
+// jsonFormatter renders alerts as JSON objects.
+type jsonFormatter struct{}
+
+// Format renders the alert.
+func (jsonFormatter) Format(ctx context.Context, severity Severity, message string) (string, error) {
+	if err := validateAlert(severity, message); err != nil {
+		return "", err
+	}
+
+	a := jsonAlert{
+		Severity: severity.String(),
+		Message:  message,
+		Metadata: nil,
+		Time:     time.Now().UTC(),
+	}
+
+	if m, ok := MetadataFromContext(ctx); ok {
+		if fields := m.fields(); len(fields) > 0 {
+			a.Metadata = fieldsMap(fields)
+		}
+	}
+
+	b, err := json.Marshal(a)
+	if err != nil {
+		return "", fmt.Errorf("marshal alert: %w", err)
+	}
+
+	return string(b), nil
+}
+
+// sortedMetadata returns the metadata as a list of fields sorted by key.
+func sortedMetadata(m map[string]string) []MetadataField {
+	fields := make([]MetadataField, 0, len(m))
+
+	for k, v := range m {
+		fields = append(fields, MetadataField{Key: k, Value: v})
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Key < fields[j].Key
+	})
+
+	return fields
+}
@@ -0,0 +1,104 @@
+package notifier_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/notifier"
+)
+
+func TestFormatters(t *testing.T) {
+	ctx := notifier.ContextWithMetadata(context.Background(), notifier.Metadata{
+		AppName: "test_app",
+		Extra: map[string]string{
+			"query": "a<b && c_d",
+		},
+	})
+
+	const message = "<b>1 + 1 = 2!</b> *bold* & [link](url)"
+
+	tests := []struct {
+		name      string
+		formatter notifier.Formatter
+		want      string
+	}{
+		{
+			name:      "html",
+			formatter: notifier.HTMLFormatter(),
+			want: "<b>⚠️ Severity:</b> WARNING\n" +
+				"<b>Alert Message:</b> &lt;b&gt;1 + 1 = 2!&lt;/b&gt; *bold* &amp; [link](url)\n" +
+				"<b>Meta:</b>\n" +
+				"\t• app_name: test_app\n" +
+				"\t• query: a&lt;b &amp;&amp; c_d",
+		},
+		{
+			name:      "markdown v2",
+			formatter: notifier.MarkdownV2Formatter(),
+			want: "*⚠️ Severity:* WARNING\n" +
+				"*Alert Message:* <b\\>1 \\+ 1 \\= 2\\!</b\\> \\*bold\\* & \\[link\\]\\(url\\)\n" +
+				"*Meta:*\n" +
+				"\t• app\\_name: test\\_app\n" +
+				"\t• query: a<b && c\\_d",
+		},
+		{
+			name:      "slack",
+			formatter: notifier.SlackFormatter(),
+			want: "*⚠️ Severity:* WARNING\n" +
+				"*Alert Message:* &lt;b&gt;1 + 1 = 2!&lt;/b&gt; *bold* &amp; [link](url)\n" +
+				"*Meta:*\n" +
+				"\t• app_name: test_app\n" +
+				"\t• query: a&lt;b &amp;&amp; c_d",
+		},
+		{
+			name:      "plain",
+			formatter: notifier.PlainFormatter(),
+			want: "⚠️ Severity: WARNING\n" +
+				"Alert Message: <b>1 + 1 = 2!</b> *bold* & [link](url)\n" +
+				"Meta:\n" +
+				"\t• app_name: test_app\n" +
+				"\t• query: a<b && c_d",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.formatter.Format(ctx, notifier.SeverityWarning, message)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+
+			_, err = tt.formatter.Format(ctx, notifier.SeverityWarning, "")
+			require.ErrorIs(t, err, notifier.ErrEmptyMessage)
+
+			_, err = tt.formatter.Format(ctx, notifier.Severity(100), message)
+			require.ErrorIs(t, err, notifier.ErrInvalidSeverity)
+		})
+	}
+}
+
+func TestJSONFormatter(t *testing.T) {
+	ctx := notifier.ContextWithMetadata(context.Background(), notifier.Metadata{
+		AppName: "test_app",
+	})
+
+	got, err := notifier.JSONFormatter().Format(ctx, notifier.SeverityCritical, "<b>message</b>")
+	require.NoError(t, err)
+
+	var alert struct {
+		Severity string            `json:"severity"`
+		Message  string            `json:"message"`
+		Metadata map[string]string `json:"metadata"`
+		Time     string            `json:"time"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(got), &alert))
+
+	assert.Equal(t, "CRITICAL", alert.Severity)
+	assert.Equal(t, "<b>message</b>", alert.Message)
+	assert.Equal(t, map[string]string{"app_name": "test_app"}, alert.Metadata)
+	assert.NotEmpty(t, alert.Time)
+}
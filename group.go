@@ -0,0 +1,323 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GroupOptions are options for the notifier created by NewGrouper.
+type GroupOptions struct {
+	// GroupBy is the list of metadata keys the alerts are grouped by in addition to the severity.
+	// If empty, alerts are grouped by app_name.
+	GroupBy []string
+	// Wait is how long the first alert of a group is buffered before the batch is sent. If zero, 30s is used.
+	Wait time.Duration
+	// Interval is the minimal time between batches of the same group. If zero, 5m is used.
+	Interval time.Duration
+	// MaxBatch is the number of alerts that flushes the group immediately. If zero, 20 is used.
+	MaxBatch int
+	// FlushSeverity is the severity that flushes the group immediately. If zero, SeverityCritical is used.
+	FlushSeverity Severity
+	// ErrorHandler is called when the batch sent in the background could not be delivered.
+	// If nil, errors are ignored.
+	ErrorHandler func(err error)
+}
+
+const (
+	defaultGroupWait     = 30 * time.Second
+	defaultGroupInterval = 5 * time.Minute
+	defaultGroupMaxBatch = 20
+)
+
+// groupedAlert is an alert buffered in a group.
+type groupedAlert struct {
+	ctx     context.Context
+	message string
+}
+
+// alertGroup is a buffer of alerts with the same group key.
+type alertGroup struct {
+	severity Severity
+	alerts   []groupedAlert
+	timer    *time.Timer
+	// timerID identifies the current timer, so a stale timer does not flush the group.
+	timerID  uint64
+	lastSent time.Time
+}
+
+// Grouper is a notifier that buffers alerts by group and sends them as a single combined message.
+type Grouper struct {
+	next Notifier
+	opts GroupOptions
+
+	mu     sync.Mutex
+	groups map[string]*alertGroup
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewGrouper returns a new notifier that groups alerts by severity and the GroupBy metadata keys.
+// The first alert of a group is buffered for Wait, then the whole group is sent as one message;
+// the next batch of the same group is sent no sooner than Interval after the previous one.
+// Reaching MaxBatch alerts or an alert of FlushSeverity or higher flushes the group immediately.
+// The combined message has the metadata of the first alert of the batch and the "alerts" field with their number.
+//
+// Close should be called on shutdown to send the buffered alerts.
+func NewGrouper(next Notifier, opts *GroupOptions) (*Grouper, error) {
+	if next == nil {
+		return nil, fmt.Errorf("notifier is nil")
+	}
+
+	g := &Grouper{
+		next:   next,
+		groups: make(map[string]*alertGroup),
+	}
+
+	if opts != nil {
+		g.opts = *opts
+	}
+
+	if len(g.opts.GroupBy) == 0 {
+		g.opts.GroupBy = []string{metadataAppName}
+	}
+
+	if g.opts.Wait <= 0 {
+		g.opts.Wait = defaultGroupWait
+	}
+
+	if g.opts.Interval <= 0 {
+		g.opts.Interval = defaultGroupInterval
+	}
+
+	if g.opts.MaxBatch <= 0 {
+		g.opts.MaxBatch = defaultGroupMaxBatch
+	}
+
+	if g.opts.FlushSeverity == severityUnknown {
+		g.opts.FlushSeverity = SeverityCritical
+	}
+
+	return g, nil
+}
+
+// Kind returns the notifier kind.
+func (g *Grouper) Kind() string {
+	return g.next.Kind()
+}
+
+// Alert adds the alert to its group. The alert is sent synchronously only when it flushes the group.
+func (g *Grouper) Alert(ctx context.Context, severity Severity, message string) error {
+	if err := validateAlert(severity, message); err != nil {
+		return err
+	}
+
+	key := g.groupKey(ctx, severity)
+
+	g.mu.Lock()
+
+	if g.closed {
+		g.mu.Unlock()
+
+		return ErrGrouperClosed
+	}
+
+	grp, ok := g.groups[key]
+	if !ok {
+		grp = &alertGroup{
+			severity: severity,
+		}
+
+		g.groups[key] = grp
+	}
+
+	grp.alerts = append(grp.alerts, groupedAlert{
+		ctx:     context.WithoutCancel(ctx),
+		message: message,
+	})
+
+	if severity >= g.opts.FlushSeverity || len(grp.alerts) >= g.opts.MaxBatch {
+		batch := g.take(grp)
+
+		g.mu.Unlock()
+
+		return g.send(batch)
+	}
+
+	if grp.timer == nil {
+		delay := g.opts.Wait
+
+		if next := time.Until(grp.lastSent.Add(g.opts.Interval)); next > delay {
+			delay = next
+		}
+
+		g.wg.Add(1)
+
+		grp.timerID++
+		id := grp.timerID
+
+		grp.timer = time.AfterFunc(delay, func() {
+			defer g.wg.Done()
+
+			g.flushGroup(grp, id)
+		})
+	}
+
+	g.mu.Unlock()
+
+	return nil
+}
+
+// Flush sends all buffered alerts immediately.
+func (g *Grouper) Flush() error {
+	g.mu.Lock()
+
+	batches := make([]alertBatch, 0, len(g.groups))
+
+	for _, grp := range g.groups {
+		if len(grp.alerts) > 0 {
+			batches = append(batches, g.take(grp))
+		}
+	}
+
+	g.mu.Unlock()
+
+	var errs []error
+
+	for _, b := range batches {
+		if err := g.send(b); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close flushes the buffered alerts and waits for the batches sent in the background.
+// Alerts sent after Close return ErrGrouperClosed.
+func (g *Grouper) Close() error {
+	g.mu.Lock()
+	g.closed = true
+	g.mu.Unlock()
+
+	err := g.Flush()
+
+	g.wg.Wait()
+
+	return err
+}
+
+// flushGroup sends the group batch when its timer fires.
+func (g *Grouper) flushGroup(grp *alertGroup, timerID uint64) {
+	g.mu.Lock()
+
+	if grp.timerID != timerID || len(grp.alerts) == 0 {
+		g.mu.Unlock()
+
+		return
+	}
+
+	batch := g.take(grp)
+
+	g.mu.Unlock()
+
+	if err := g.send(batch); err != nil && g.opts.ErrorHandler != nil {
+		g.opts.ErrorHandler(err)
+	}
+}
+
+// alertBatch is a set of alerts of one group sent as a single message.
+type alertBatch struct {
+	severity Severity
+	alerts   []groupedAlert
+}
+
+// take removes the buffered alerts from the group and stops its timer. Groups that have been idle
+// for Interval are removed. Must be called with mu held.
+func (g *Grouper) take(grp *alertGroup) alertBatch {
+	if grp.timer != nil && grp.timer.Stop() {
+		g.wg.Done()
+	}
+
+	grp.timer = nil
+	grp.lastSent = time.Now()
+
+	batch := alertBatch{
+		severity: grp.severity,
+		alerts:   grp.alerts,
+	}
+
+	grp.alerts = nil
+
+	// Idle groups are kept for Interval only to delay the next batch.
+	for key, idle := range g.groups {
+		if len(idle.alerts) == 0 && idle.timer == nil && time.Since(idle.lastSent) >= g.opts.Interval {
+			delete(g.groups, key)
+		}
+	}
+
+	return batch
+}
+
+// send sends the batch. A single alert is sent as is, with its own context.
+func (g *Grouper) send(b alertBatch) error {
+	if len(b.alerts) == 1 {
+		return g.next.Alert(b.alerts[0].ctx, b.severity, b.alerts[0].message)
+	}
+
+	// The alerts of the group share the grouped metadata, the rest is taken from the first alert.
+	ctx := ContextWithField(b.alerts[0].ctx, "alerts", strconv.Itoa(len(b.alerts)))
+
+	return g.next.Alert(ctx, b.severity, groupMessage(b.alerts))
+}
+
+// groupKey returns the key of the alert group.
+func (g *Grouper) groupKey(ctx context.Context, severity Severity) string {
+	var values map[string]string
+
+	if m, ok := MetadataFromContext(ctx); ok {
+		values = fieldsMap(m.fields())
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(severity.String())
+
+	for _, k := range g.opts.GroupBy {
+		sb.WriteString("\x00" + values[k])
+	}
+
+	return sb.String()
+}
+
+// groupMessage lists the messages of the alerts, repeated messages are counted once.
+func groupMessage(alerts []groupedAlert) string {
+	order := make([]string, 0, len(alerts))
+	counts := make(map[string]int, len(alerts))
+
+	for _, a := range alerts {
+		if counts[a.message] == 0 {
+			order = append(order, a.message)
+		}
+
+		counts[a.message]++
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%d alerts:", len(alerts)))
+
+	for _, msg := range order {
+		sb.WriteString("\n- " + msg)
+
+		if n := counts[msg]; n > 1 {
+			sb.WriteString(fmt.Sprintf(" (x%d)", n))
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,89 @@
+package notifier_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/notifier"
+)
+
+func TestGrouper(t *testing.T) {
+	n, alerts := newRecordingNotifier()
+
+	g, err := notifier.NewGrouper(n, &notifier.GroupOptions{
+		Wait:     50 * time.Millisecond,
+		Interval: 50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	api := notifier.ContextWithMetadata(context.Background(), notifier.Metadata{AppName: "api", InstanceName: "api-1"})
+	worker := notifier.ContextWithMetadata(context.Background(), notifier.Metadata{AppName: "worker"})
+
+	require.NoError(t, g.Alert(api, notifier.SeverityWarning, "db is slow"))
+	require.NoError(t, g.Alert(api, notifier.SeverityWarning, "db is slow"))
+	require.NoError(t, g.Alert(api, notifier.SeverityWarning, "cache miss"))
+	require.NoError(t, g.Alert(worker, notifier.SeverityWarning, "queue is full"))
+
+	assert.Empty(t, alerts)
+
+	got := map[string]recordedAlert{}
+
+	for range 2 {
+		a := waitAlert(t, alerts)
+		got[a.message] = a
+	}
+
+	batch, ok := got["3 alerts:\n- db is slow (x2)\n- cache miss"]
+	require.True(t, ok, got)
+	assert.Equal(t, notifier.SeverityWarning, batch.severity)
+	assert.Equal(t, notifier.Metadata{
+		AppName:      "api",
+		InstanceName: "api-1",
+		Fields: []notifier.MetadataField{
+			{Key: "alerts", Value: "3"},
+		},
+	}, batch.metadata)
+
+	single, ok := got["queue is full"]
+	require.True(t, ok, got)
+	assert.Equal(t, "worker", single.metadata.AppName)
+
+	require.NoError(t, g.Close())
+	require.ErrorIs(t, g.Alert(api, notifier.SeverityWarning, "late"), notifier.ErrGrouperClosed)
+}
+
+func TestGrouper_Flush(t *testing.T) {
+	n, alerts := newRecordingNotifier()
+
+	g, err := notifier.NewGrouper(n, &notifier.GroupOptions{
+		Wait:     time.Hour,
+		MaxBatch: 3,
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// Critical alert is sent immediately.
+	require.NoError(t, g.Alert(ctx, notifier.SeverityCritical, "database is down"))
+	assert.Equal(t, "database is down", waitAlert(t, alerts).message)
+
+	// Max batch flushes the group.
+	for _, msg := range []string{"one", "two", "three"} {
+		require.NoError(t, g.Alert(ctx, notifier.SeverityInfo, msg))
+	}
+
+	assert.Equal(t, "3 alerts:\n- one\n- two\n- three", waitAlert(t, alerts).message)
+
+	// Close sends the buffered alerts.
+	require.NoError(t, g.Alert(ctx, notifier.SeverityInfo, "buffered"))
+	assert.Empty(t, alerts)
+
+	require.NoError(t, g.Close())
+	assert.Equal(t, "buffered", waitAlert(t, alerts).message)
+
+	require.ErrorIs(t, g.Alert(ctx, notifier.SeverityInfo, ""), notifier.ErrEmptyMessage)
+}
@@ -0,0 +1,250 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HeartbeatOptions are options for the monitor created by NewHeartbeat.
+type HeartbeatOptions struct {
+	// CheckInterval is how often Run checks for missed beats. If zero, 10s is used.
+	CheckInterval time.Duration
+	// ErrorHandler is called when the alert sent by Run could not be delivered. If nil, errors are ignored.
+	ErrorHandler func(err error)
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+}
+
+const defaultHeartbeatCheckInterval = 10 * time.Second
+
+// heartbeatMonitor is the state of a watched heartbeat.
+type heartbeatMonitor struct {
+	interval time.Duration
+	lastBeat time.Time
+	// missedAt is the time the missed beat was reported, zero if the heartbeat is healthy.
+	missedAt time.Time
+	// recovered is set when the beat resumes after it was reported as missed.
+	recovered bool
+}
+
+// Heartbeat is a dead man's switch: it raises an alert when a watched heartbeat stops beating.
+type Heartbeat struct {
+	notifier Notifier
+	opts     HeartbeatOptions
+
+	mu       sync.Mutex
+	monitors map[string]*heartbeatMonitor
+}
+
+// NewHeartbeat returns a new heartbeat monitor sending alerts to n.
+//
+// Watched heartbeats should call Beat at least once per their interval. When a beat is missed,
+// a SeverityCritical alert is sent once; when the beats resume, a SeverityInfo recovery alert is sent.
+// Run should be started to check the heartbeats periodically.
+func NewHeartbeat(n Notifier, opts *HeartbeatOptions) (*Heartbeat, error) {
+	if n == nil {
+		return nil, fmt.Errorf("notifier is nil")
+	}
+
+	h := &Heartbeat{
+		notifier: n,
+		monitors: make(map[string]*heartbeatMonitor),
+	}
+
+	if opts != nil {
+		h.opts = *opts
+	}
+
+	if h.opts.CheckInterval <= 0 {
+		h.opts.CheckInterval = defaultHeartbeatCheckInterval
+	}
+
+	if h.opts.Now == nil {
+		h.opts.Now = time.Now
+	}
+
+	return h, nil
+}
+
+// Watch starts watching the heartbeat expected at least once per interval.
+// The first beat is expected within interval from now. Watching the same name again updates its interval.
+func (h *Heartbeat) Watch(name string, interval time.Duration) error {
+	if name == "" {
+		return fmt.Errorf("heartbeat name is empty")
+	}
+
+	if interval <= 0 {
+		return fmt.Errorf("heartbeat '%s': interval should be positive", name)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if m, ok := h.monitors[name]; ok {
+		m.interval = interval
+
+		return nil
+	}
+
+	h.monitors[name] = &heartbeatMonitor{
+		interval: interval,
+		lastBeat: h.opts.Now(),
+	}
+
+	return nil
+}
+
+// Unwatch stops watching the heartbeat.
+func (h *Heartbeat) Unwatch(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.monitors, name)
+}
+
+// Beat records a beat of the heartbeat.
+func (h *Heartbeat) Beat(name string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	m, ok := h.monitors[name]
+	if !ok {
+		return fmt.Errorf("'%s': %w", name, ErrUnknownHeartbeat)
+	}
+
+	m.lastBeat = h.opts.Now()
+
+	if !m.missedAt.IsZero() {
+		m.recovered = true
+	}
+
+	return nil
+}
+
+// heartbeatAlert is an alert raised by Check.
+type heartbeatAlert struct {
+	name     string
+	severity Severity
+	message  string
+	fields   []MetadataField
+	// monitor and missedAt are set for the missed beat alerts, to report the miss again if the alert fails.
+	monitor  *heartbeatMonitor
+	missedAt time.Time
+}
+
+// Check sends alerts for the heartbeats that missed their beat and recovery alerts for the ones that resumed.
+func (h *Heartbeat) Check(ctx context.Context) error {
+	now := h.opts.Now()
+
+	h.mu.Lock()
+
+	var alerts []heartbeatAlert
+
+	for name, m := range h.monitors {
+		fields := []MetadataField{
+			{Key: "heartbeat", Value: name},
+			{Key: "interval", Value: m.interval.String()},
+			{Key: "last_beat", Value: m.lastBeat.UTC().Format(time.DateTime)},
+		}
+
+		switch {
+		case m.recovered:
+			alerts = append(alerts, heartbeatAlert{
+				name:     name,
+				severity: SeverityInfo,
+				message:  fmt.Sprintf("heartbeat '%s' recovered after %s", name, m.lastBeat.Sub(m.missedAt).Round(time.Second)),
+				fields:   fields,
+			})
+
+			m.missedAt = time.Time{}
+			m.recovered = false
+		case m.missedAt.IsZero() && now.Sub(m.lastBeat) > m.interval:
+			alerts = append(alerts, heartbeatAlert{
+				name:     name,
+				severity: SeverityCritical,
+				message: fmt.Sprintf("heartbeat '%s' missed: no beat for %s, expected every %s",
+					name, now.Sub(m.lastBeat).Round(time.Second), m.interval),
+				fields:   fields,
+				monitor:  m,
+				missedAt: now,
+			})
+
+			m.missedAt = now
+		}
+	}
+
+	h.mu.Unlock()
+
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].name < alerts[j].name
+	})
+
+	var errs []error
+
+	for _, a := range alerts {
+		if err := h.notifier.Alert(ContextWithFields(ctx, a.fields...), a.severity, a.message); err != nil {
+			errs = append(errs, fmt.Errorf("heartbeat '%s': %w", a.name, err))
+
+			h.rollback(a)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// rollback marks the heartbeat of the undelivered missed beat alert as healthy, so the next Check reports it again.
+func (h *Heartbeat) rollback(a heartbeatAlert) {
+	if a.monitor == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.monitors[a.name] == a.monitor && a.monitor.missedAt.Equal(a.missedAt) {
+		a.monitor.missedAt = time.Time{}
+		a.monitor.recovered = false
+	}
+}
+
+// Run checks the heartbeats every CheckInterval until ctx is canceled.
+func (h *Heartbeat) Run(ctx context.Context) error {
+	ticker := time.NewTicker(h.opts.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := h.Check(ctx); err != nil && h.opts.ErrorHandler != nil {
+				h.opts.ErrorHandler(err)
+			}
+		}
+	}
+}
+
+// ServeHTTP records a beat of the heartbeat named by the last element of the request path,
+// e.g. POST /heartbeat/nightly-backup, so jobs in other processes could beat with curl.
+func (h *Heartbeat) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	if err := h.Beat(path.Base(r.URL.Path)); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
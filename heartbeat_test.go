@@ -0,0 +1,154 @@
+package notifier_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/notifier"
+)
+
+func TestHeartbeat(t *testing.T) {
+	n, alerts := newRecordingNotifier()
+
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	h, err := notifier.NewHeartbeat(n, &notifier.HeartbeatOptions{
+		Now: func() time.Time { return now },
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, h.Watch("backup", time.Hour))
+	require.ErrorIs(t, h.Beat("unknown"), notifier.ErrUnknownHeartbeat)
+
+	ctx := context.Background()
+
+	now = now.Add(30 * time.Minute)
+
+	require.NoError(t, h.Beat("backup"))
+	require.NoError(t, h.Check(ctx))
+	assert.Empty(t, alerts)
+
+	// The beat is missed.
+	now = now.Add(90 * time.Minute)
+
+	require.NoError(t, h.Check(ctx))
+
+	missed := waitAlert(t, alerts)
+	assert.Equal(t, notifier.SeverityCritical, missed.severity)
+	assert.Equal(t, "heartbeat 'backup' missed: no beat for 1h30m0s, expected every 1h0m0s", missed.message)
+	assert.Equal(t, []notifier.MetadataField{
+		{Key: "heartbeat", Value: "backup"},
+		{Key: "interval", Value: "1h0m0s"},
+		{Key: "last_beat", Value: "2024-01-01 00:30:00"},
+	}, missed.metadata.Fields)
+
+	// The missed beat is reported once.
+	now = now.Add(time.Hour)
+
+	require.NoError(t, h.Check(ctx))
+	assert.Empty(t, alerts)
+
+	// The beats resume.
+	require.NoError(t, h.Beat("backup"))
+	require.NoError(t, h.Check(ctx))
+
+	recovered := waitAlert(t, alerts)
+	assert.Equal(t, notifier.SeverityInfo, recovered.severity)
+	assert.Equal(t, "heartbeat 'backup' recovered after 1h0m0s", recovered.message)
+
+	require.NoError(t, h.Check(ctx))
+	assert.Empty(t, alerts)
+
+	h.Unwatch("backup")
+	require.ErrorIs(t, h.Beat("backup"), notifier.ErrUnknownHeartbeat)
+}
+
+func TestHeartbeat_MissedAlertFailed(t *testing.T) {
+	var sent []string
+
+	failing := true
+
+	n := notifierFunc(func(_ context.Context, _ notifier.Severity, message string) error {
+		if failing {
+			return errors.New("unavailable")
+		}
+
+		sent = append(sent, message)
+
+		return nil
+	})
+
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	h, err := notifier.NewHeartbeat(n, &notifier.HeartbeatOptions{
+		Now: func() time.Time { return now },
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, h.Watch("backup", time.Hour))
+
+	now = now.Add(2 * time.Hour)
+
+	require.ErrorContains(t, h.Check(context.Background()), "unavailable")
+
+	// The undelivered miss is reported again.
+	failing = false
+
+	require.NoError(t, h.Check(context.Background()))
+	assert.Equal(t, []string{"heartbeat 'backup' missed: no beat for 2h0m0s, expected every 1h0m0s"}, sent)
+}
+
+func TestHeartbeat_ServeHTTP(t *testing.T) {
+	n, _ := newRecordingNotifier()
+
+	h, err := notifier.NewHeartbeat(n, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, h.Watch("backup", time.Hour))
+
+	for _, tt := range []struct {
+		method   string
+		path     string
+		wantCode int
+	}{
+		{method: http.MethodPost, path: "/heartbeat/backup", wantCode: http.StatusNoContent},
+		{method: http.MethodPost, path: "/heartbeat/unknown", wantCode: http.StatusNotFound},
+		{method: http.MethodGet, path: "/heartbeat/backup", wantCode: http.StatusMethodNotAllowed},
+	} {
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+		assert.Equal(t, tt.wantCode, rec.Code, tt.method+" "+tt.path)
+	}
+}
+
+func TestHeartbeat_Run(t *testing.T) {
+	n, alerts := newRecordingNotifier()
+
+	h, err := notifier.NewHeartbeat(n, &notifier.HeartbeatOptions{CheckInterval: 5 * time.Millisecond})
+	require.NoError(t, err)
+
+	require.NoError(t, h.Watch("job", 10*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error)
+
+	go func() {
+		done <- h.Run(ctx)
+	}()
+
+	assert.Equal(t, notifier.SeverityCritical, waitAlert(t, alerts).severity)
+
+	cancel()
+
+	require.ErrorIs(t, <-done, context.Canceled)
+}
@@ -0,0 +1,244 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// HTTPMiddlewareOptions are options for the middleware created by NewHTTPMiddleware.
+type HTTPMiddlewareOptions struct {
+	// Throttle is the minimal interval between alerts for the same route.
+	// Zero means that every failed request raises an alert.
+	Throttle time.Duration
+	// RequestIDHeader is the header with the request ID. If empty, "X-Request-Id" is used.
+	RequestIDHeader string
+	// Route returns the route of the request used in alerts and for throttling.
+	// If nil, the http.ServeMux pattern is used, falling back to the URL path.
+	Route func(r *http.Request) string
+	// MaxPending is the maximal number of alerts sent in the background at once. Alerts over it are
+	// dropped and reported to the ErrorHandler with ErrTooManyPendingAlerts. If zero, 100 is used.
+	MaxPending int
+	// ErrorHandler is called when the alert could not be sent. If nil, errors are ignored.
+	ErrorHandler func(err error)
+}
+
+const (
+	defaultRequestIDHeader = "X-Request-Id"
+	defaultMaxPending      = 100
+)
+
+// httpMiddleware raises alerts on 5xx responses and panics.
+type httpMiddleware struct {
+	notifier Notifier
+	opts     HTTPMiddlewareOptions
+
+	// pending limits the number of alerts sent in the background.
+	pending chan struct{}
+
+	mu sync.Mutex
+	// lastAlert holds the time of the last alert per route within Throttle.
+	lastAlert map[string]time.Time
+	// lastPrune is the time expired routes were removed from lastAlert.
+	lastPrune time.Time
+}
+
+// NewHTTPMiddleware returns a new net/http middleware that sends alerts when the handler
+// responds with 5xx status (SeverityWarning) or panics (SeverityCritical).
+// Panics are recovered and answered with 500 Internal Server Error.
+//
+// Alerts are sent in the background, so failed requests are not delayed by the delivery;
+// at most MaxPending alerts are sent at once.
+// The request method, route, status, latency and request ID are added to the Metadata extra fields.
+func NewHTTPMiddleware(n Notifier, opts *HTTPMiddlewareOptions) (func(http.Handler) http.Handler, error) {
+	if n == nil {
+		return nil, fmt.Errorf("notifier is nil")
+	}
+
+	m := &httpMiddleware{
+		notifier:  n,
+		lastAlert: make(map[string]time.Time),
+	}
+
+	if opts != nil {
+		m.opts = *opts
+	}
+
+	if m.opts.RequestIDHeader == "" {
+		m.opts.RequestIDHeader = defaultRequestIDHeader
+	}
+
+	if m.opts.Route == nil {
+		m.opts.Route = requestRoute
+	}
+
+	if m.opts.MaxPending <= 0 {
+		m.opts.MaxPending = defaultMaxPending
+	}
+
+	m.pending = make(chan struct{}, m.opts.MaxPending)
+
+	return m.wrap, nil
+}
+
+// requestRoute returns the http.ServeMux pattern or the URL path of the request.
+func requestRoute(r *http.Request) string {
+	if r.Pattern != "" {
+		return r.Pattern
+	}
+
+	return r.URL.Path
+}
+
+func (m *httpMiddleware) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		rw := &statusWriter{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+		}
+
+		defer func() {
+			p := recover()
+			if p == nil {
+				if rw.status >= http.StatusInternalServerError {
+					m.alert(r, rw.status, time.Since(start), SeverityWarning,
+						fmt.Sprintf("%s %s: %d %s", r.Method, r.URL.Path, rw.status, http.StatusText(rw.status)))
+				}
+
+				return
+			}
+
+			//nolint:errorlint,err113 // http.ErrAbortHandler is a sentinel value of the panic.
+			if p == http.ErrAbortHandler {
+				panic(p)
+			}
+
+			err, ok := p.(error)
+			if !ok {
+				err = fmt.Errorf("%v", p)
+			}
+
+			if !rw.wroteHeader {
+				http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+
+			frames := panicFrames(callers(1))
+
+			m.alert(r, http.StatusInternalServerError, time.Since(start), SeverityCritical,
+				renderErrorAlert(fmt.Sprintf("%s %s: panic", r.Method, r.URL.Path), err, frames))
+		}()
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// allow reports whether the alert for the route is not throttled.
+func (m *httpMiddleware) allow(route string, now time.Time) bool {
+	if m.opts.Throttle <= 0 {
+		return true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if last, ok := m.lastAlert[route]; ok && now.Sub(last) < m.opts.Throttle {
+		return false
+	}
+
+	m.lastAlert[route] = now
+
+	// Routes are taken from the request paths, so the routes not alerted within Throttle are removed.
+	if now.Sub(m.lastPrune) >= m.opts.Throttle {
+		for r, last := range m.lastAlert {
+			if now.Sub(last) >= m.opts.Throttle {
+				delete(m.lastAlert, r)
+			}
+		}
+
+		m.lastPrune = now
+	}
+
+	return true
+}
+
+func (m *httpMiddleware) alert(r *http.Request, status int, latency time.Duration, severity Severity, message string) {
+	route := m.opts.Route(r)
+
+	if !m.allow(route, time.Now()) {
+		return
+	}
+
+	md := Metadata{
+		Extra: map[string]string{
+			"http_method": r.Method,
+			"http_route":  route,
+			"http_status": strconv.Itoa(status),
+			"latency":     latency.String(),
+		},
+	}
+
+	if id := r.Header.Get(m.opts.RequestIDHeader); id != "" {
+		md.Extra["request_id"] = id
+	}
+
+	// The request context is canceled when the handler returns.
+	ctx := ContextWithMetadata(context.WithoutCancel(r.Context()), md)
+
+	select {
+	case m.pending <- struct{}{}:
+	default:
+		if m.opts.ErrorHandler != nil {
+			m.opts.ErrorHandler(fmt.Errorf("send http alert: %w", ErrTooManyPendingAlerts))
+		}
+
+		return
+	}
+
+	go func() {
+		defer func() { <-m.pending }()
+
+		err := m.notifier.Alert(ctx, severity, message)
+		if err != nil && m.opts.ErrorHandler != nil {
+			m.opts.ErrorHandler(fmt.Errorf("send http alert: %w", err))
+		}
+	}()
+}
+
+// statusWriter records the response status.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+// WriteHeader records the status and writes it to the wrapped writer.
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write writes the data to the wrapped writer.
+func (w *statusWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush flushes the wrapped writer if it supports flushing.
+func (w *statusWriter) Flush() {
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped writer, used by http.ResponseController.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,176 @@
+package notifier_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/notifier"
+)
+
+type recordedAlert struct {
+	severity notifier.Severity
+	message  string
+	metadata notifier.Metadata
+}
+
+// newRecordingNotifier returns a notifier that sends every alert to the channel.
+func newRecordingNotifier() (notifier.Notifier, chan recordedAlert) {
+	ch := make(chan recordedAlert, 10)
+
+	return notifierFunc(func(ctx context.Context, severity notifier.Severity, message string) error {
+		a := recordedAlert{
+			severity: severity,
+			message:  message,
+		}
+
+		if m, ok := notifier.MetadataFromContext(ctx); ok {
+			a.metadata = *m
+		}
+
+		ch <- a
+
+		return nil
+	}), ch
+}
+
+func waitAlert(tb testing.TB, ch chan recordedAlert) recordedAlert {
+	tb.Helper()
+
+	select {
+	case a := <-ch:
+		return a
+	case <-time.After(time.Second):
+		tb.Fatal("alert was not sent")
+	}
+
+	return recordedAlert{}
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	n, alerts := newRecordingNotifier()
+
+	mw, err := notifier.NewHTTPMiddleware(n, &notifier.HTTPMiddlewareOptions{
+		Throttle: time.Hour,
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /ok", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("GET /unavailable/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("GET /panic", func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	})
+
+	h := mw(mux)
+
+	ctx := notifier.ContextWithMetadata(context.Background(), notifier.Metadata{AppName: "test_app"})
+
+	serve := func(path string) int {
+		req := httptest.NewRequestWithContext(ctx, http.MethodGet, path, http.NoBody)
+		req.Header.Set("X-Request-Id", "req-1")
+
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve("/ok"))
+
+	assert.Equal(t, http.StatusServiceUnavailable, serve("/unavailable/1"))
+
+	a := waitAlert(t, alerts)
+	assert.Equal(t, notifier.SeverityWarning, a.severity)
+	assert.Equal(t, "GET /unavailable/1: 503 Service Unavailable", a.message)
+	assert.Equal(t, "test_app", a.metadata.AppName)
+	assert.Equal(t, "GET", a.metadata.Extra["http_method"])
+	assert.Equal(t, "GET /unavailable/{id}", a.metadata.Extra["http_route"])
+	assert.Equal(t, "503", a.metadata.Extra["http_status"])
+	assert.Equal(t, "req-1", a.metadata.Extra["request_id"])
+	assert.NotEmpty(t, a.metadata.Extra["latency"])
+
+	// Same route is throttled.
+	assert.Equal(t, http.StatusServiceUnavailable, serve("/unavailable/2"))
+
+	assert.Equal(t, http.StatusInternalServerError, serve("/panic"))
+
+	a = waitAlert(t, alerts)
+	assert.Equal(t, notifier.SeverityCritical, a.severity)
+	assert.Contains(t, a.message, "GET /panic: panic: boom")
+	assert.Contains(t, a.message, "middleware_test.go")
+	assert.Equal(t, "500", a.metadata.Extra["http_status"])
+
+	select {
+	case a = <-alerts:
+		t.Fatalf("unexpected alert: %v", a)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHTTPMiddleware_AbortHandler(t *testing.T) {
+	n, alerts := newRecordingNotifier()
+
+	mw, err := notifier.NewHTTPMiddleware(n, nil)
+	require.NoError(t, err)
+
+	h := mw(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+	})
+
+	assert.Empty(t, alerts)
+}
+
+func TestHTTPMiddleware_MaxPending(t *testing.T) {
+	release := make(chan struct{})
+
+	n := notifierFunc(func(context.Context, notifier.Severity, string) error {
+		<-release
+
+		return nil
+	})
+
+	errs := make(chan error, 1)
+
+	mw, err := notifier.NewHTTPMiddleware(n, &notifier.HTTPMiddlewareOptions{
+		MaxPending: 1,
+		ErrorHandler: func(err error) {
+			errs <- err
+		},
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		close(release)
+	})
+
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	for _, path := range []string{"/first", "/second"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, http.NoBody))
+	}
+
+	// The first alert is still being sent, so the second one is dropped.
+	select {
+	case err = <-errs:
+		require.ErrorIs(t, err, notifier.ErrTooManyPendingAlerts)
+	case <-time.After(time.Second):
+		t.Fatal("dropped alert was not reported")
+	}
+}
@@ -6,6 +6,7 @@
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"strconv"
 	"strings"
 
@@ -20,8 +21,62 @@ type Notifier interface {
 	Kind() string
 }
 
-func isInvalidToken(err error) bool {
-	return strings.Contains(err.Error(), "Not Found")
+// Option configures a notifier.
+type Option func(*options)
+
+// options holds the notifier configuration.
+type options struct {
+	formatter   Formatter
+	kind        string
+	apiEndpoint string
+}
+
+func newOptions(formatter Formatter, opts []Option) options {
+	o := options{
+		formatter: formatter,
+		kind:      "",
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// WithFormatter sets the formatter used to render alerts.
+// By default, telegram notifier uses HTMLFormatter and io.Writer notifier uses PlainFormatter.
+func WithFormatter(f Formatter) Option {
+	return func(o *options) {
+		if f != nil {
+			o.formatter = f
+		}
+	}
+}
+
+// WithTemplates sets the templates used to render alerts.
+func WithTemplates(t *Templates) Option {
+	return func(o *options) {
+		if t != nil {
+			o.formatter = t
+		}
+	}
+}
+
+// WithAPIEndpoint sets the Bot API endpoint in the format of tgbotapi.APIEndpoint,
+// e.g. a local Bot API server "http://localhost:8081/bot%s/%s". Supported by NewTelegram.
+func WithAPIEndpoint(endpoint string) Option {
+	return func(o *options) {
+		o.apiEndpoint = endpoint
+	}
+}
+
+// WithKind adds the name to the notifier kind, e.g. "iowriter: name".
+// Supported by NewWriterNotifier.
+func WithKind(kind string) Option {
+	return func(o *options) {
+		o.kind = kind
+	}
 }
 
 // telegramNotifier sends messages to a telegram chat.
@@ -30,10 +85,12 @@ type telegramNotifier struct {
 	chatID int64
 	// Telegram client.
 	client *tgbotapi.BotAPI
+	// Alert formatter.
+	formatter Formatter
 }
 
 // NewTelegram returns a new telegram notifier.
-func NewTelegram(token, chatID string) (Notifier, error) {
+func NewTelegram(token, chatID string, opts ...Option) (Notifier, error) {
 	if token == "" {
 		return nil, ErrEmptyTelegramToken
 	}
@@ -42,13 +99,23 @@ func NewTelegram(token, chatID string) (Notifier, error) {
 		return nil, ErrEmptyTelegramChatID
 	}
 
-	client, err := tgbotapi.NewBotAPI(token)
+	o := newOptions(HTMLFormatter(), opts)
+
+	endpoint := o.apiEndpoint
+	if endpoint == "" {
+		endpoint = tgbotapi.APIEndpoint
+	}
+
+	client, err := tgbotapi.NewBotAPIWithAPIEndpoint(token, endpoint)
 	if err != nil {
-		if isInvalidToken(err) {
-			err = ErrInvalidToken
+		derr := telegramDeliveryError(context.Background(), err)
+
+		// Telegram responds with 404 to malformed tokens and 401 to revoked ones.
+		if derr.StatusCode == http.StatusNotFound || errors.Is(derr, ErrUnauthorized) {
+			return nil, fmt.Errorf("create telegram client: %w: %w", ErrInvalidToken, derr)
 		}
 
-		return nil, fmt.Errorf("create telegram client: %w", err)
+		return nil, fmt.Errorf("create telegram client: %w", derr)
 	}
 
 	id, err := strconv.ParseInt(chatID, 10, 64)
@@ -57,8 +124,9 @@ func NewTelegram(token, chatID string) (Notifier, error) {
 	}
 
 	return &telegramNotifier{
-		chatID: id,
-		client: client,
+		chatID:    id,
+		client:    client,
+		formatter: o.formatter,
 	}, nil
 }
 
@@ -76,41 +144,59 @@ func (t *telegramNotifier) Kind() string {
 
 // Alert sends a message to the telegram chat.
 func (t *telegramNotifier) Alert(ctx context.Context, severity Severity, message string) error {
-	alert, err := formatAlert(ctx, severity, message)
+	alert, err := t.formatter.Format(ctx, severity, message)
 	if err != nil {
 		return fmt.Errorf("format alert: %w", err)
 	}
 
 	msg := tgbotapi.NewMessage(t.chatID, alert)
-	// Telegram messages should be sent in HTML format.
+	// Telegram messages are sent in the parse mode of the formatter, HTML by default.
 	// https://confluence.softswiss.com/display/ADT/Alerting+notes
-	msg.ParseMode = tgbotapi.ModeHTML
+	msg.ParseMode = formatterParseMode(t.formatter)
 
 	_, err = t.client.Send(msg)
 	if err != nil {
-		return fmt.Errorf("send telegram message failed: %w", err)
+		return fmt.Errorf("send telegram message failed: %w", telegramDeliveryError(ctx, err))
 	}
 
 	return nil
 }
 
 // multiNotifier is a notifier that sends messages to multiple notifiers.
-type multiNotifier []Notifier
+type multiNotifier struct {
+	notifiers []Notifier
+	policy    DeliveryPolicy
+}
 
 // NewMultiNotifier returns a new multiNotifier notifier.
 // Useful when it's needed to send messages to multiple telegram chats or other notifiers.
+// The alert is delivered only if all notifiers succeed, see NewMultiNotifierWithPolicy for other policies.
 func NewMultiNotifier(notifiers ...Notifier) (Notifier, error) {
+	return NewMultiNotifierWithPolicy(DeliverAll(), notifiers...)
+}
+
+// NewMultiNotifierWithPolicy returns a new multiNotifier notifier that decides whether the alert
+// is delivered according to the policy.
+func NewMultiNotifierWithPolicy(policy DeliveryPolicy, notifiers ...Notifier) (Notifier, error) {
 	if len(notifiers) == 0 {
 		return nil, ErrEmptyNotifiers
 	}
 
-	return multiNotifier(notifiers), nil
+	if policy.MinSuccess < 0 || policy.MinSuccess > len(notifiers) {
+		return nil, fmt.Errorf("policy requires %d successful notifiers of %d: %w",
+			policy.MinSuccess, len(notifiers), ErrInvalidPolicy)
+	}
+
+	return multiNotifier{
+		notifiers: notifiers,
+		policy:    policy,
+	}, nil
 }
 
 func (m multiNotifier) Kind() string {
-	kinds := make([]string, 0, len(m))
+	kinds := make([]string, 0, len(m.notifiers))
 
-	for _, n := range m {
+	for _, n := range m.notifiers {
 		kinds = append(kinds, n.Kind())
 	}
 
@@ -119,44 +205,67 @@ func (m multiNotifier) Kind() string {
 
 // Alert sends a message to all notifiers.
 func (m multiNotifier) Alert(ctx context.Context, severity Severity, message string) error {
-	var errs error
+	var (
+		errs      error
+		delivered int
+	)
 
-	for _, notifier := range m {
+	for _, notifier := range m.notifiers {
 		err := notifier.Alert(ctx, severity, message)
 		if err != nil {
-			if errors.Is(err, ErrEmptyMessage) || errors.Is(err, ErrInvalidSeverity) {
+			if !m.policy.ContinueOnInvalid && (errors.Is(err, ErrEmptyMessage) || errors.Is(err, ErrInvalidSeverity)) {
 				// If the message is empty, there is no need to send it to other notifiers.
 				return fmt.Errorf("send alert to '%s': %w", m.Kind(), err)
 			}
 
 			errs = errors.Join(errs, fmt.Errorf("send alert to '%s': %w", notifier.Kind(), err))
+
+			continue
 		}
+
+		delivered++
 	}
 
-	return errs
+	return m.policy.result(delivered, len(m.notifiers), errs)
 }
 
 // iowriterNotifier is a notifier that writes messages to io.Writer.
 type iowriterNotifier struct {
-	w    io.Writer
-	kind string
+	w         io.Writer
+	kind      string
+	formatter Formatter
 }
 
 // NewIOWriterNotifier creates a new notifier that writes messages to io.Writer.
-// Useful for testing.
+// Useful for testing and logging, alerts are written as plain text.
 // If kind is not provided, "iowriter" is used.
 func NewIOWriterNotifier(w io.Writer, kind ...string) (Notifier, error) {
+	var opts []Option
+
+	if len(kind) > 0 {
+		opts = append(opts, WithKind(strings.Join(kind, " ")))
+	}
+
+	return NewWriterNotifier(w, opts...)
+}
+
+// NewWriterNotifier creates a new notifier that writes messages to io.Writer.
+// It's the same as NewIOWriterNotifier, but accepts options.
+func NewWriterNotifier(w io.Writer, opts ...Option) (Notifier, error) {
 	if w == nil {
 		return nil, fmt.Errorf("io.Writer is nil")
 	}
 
+	o := newOptions(PlainFormatter(), opts)
+
 	n := &iowriterNotifier{
-		w:    w,
-		kind: "iowriter",
+		w:         w,
+		kind:      "iowriter",
+		formatter: o.formatter,
 	}
 
-	if len(kind) > 0 {
-		n.kind += ": " + strings.Join(kind, " ")
+	if o.kind != "" {
+		n.kind += ": " + o.kind
 	}
 
 	return n, nil
@@ -169,14 +278,14 @@ func (n *iowriterNotifier) Kind() string {
 
 // Alert sends a message to the io.Writer.
 func (n *iowriterNotifier) Alert(ctx context.Context, severity Severity, msg string) error {
-	alert, err := formatAlert(ctx, severity, msg)
+	alert, err := n.formatter.Format(ctx, severity, msg)
 	if err != nil {
 		return fmt.Errorf("format alert: %w", err)
 	}
 
 	_, err = fmt.Fprintln(n.w, alert)
 	if err != nil {
-		return fmt.Errorf("write alert: %w", err)
+		return fmt.Errorf("write alert: %w", &DeliveryError{Kind: n.kind, Err: err})
 	}
 
 	return nil
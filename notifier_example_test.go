@@ -71,11 +71,11 @@ func ExampleNotifier() {
 
 	fmt.Println(buf.String())
 	// Output:
-	// <b>⚠️ Severity:</b> WARNING
-	// <b>Alert Message:</b> [NOTIFIER_TEST]: example message
-	// <b>Meta:</b>
+	// ⚠️ Severity: WARNING
+	// Alert Message: [NOTIFIER_TEST]: example message
+	// Meta:
 	//	• app_name: test_app
-	//	• build_date: 2020-01-01 00:00:00
-	//	• commit: test_commit
 	//	• instance_name: test_instance
+	//	• commit: test_commit
+	//	• build_date: 2020-01-01 00:00:00
 }
@@ -3,11 +3,15 @@
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/obalunenko/getenv"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/obalunenko/notifier"
@@ -87,7 +91,7 @@ type testcase struct {
 			wantErr: func(t require.TestingT, err error, i ...interface{}) {
 				require.EqualError(t, err,
 					"send alert to 'multi[iowriter: one;iowriter: two]': format alert: 'Severity(100)', "+
-						"should be one of '[INFO WARNING CRITICAL]': invalid severity", i)
+						"should be one of '[DEBUG INFO WARNING ERROR CRITICAL EMERGENCY]': invalid severity", i)
 			},
 		},
 	}
@@ -110,6 +114,49 @@ type testcase struct {
 	}
 }
 
+func TestNewTelegram_InvalidToken(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		description string
+		wantErr     string
+	}{
+		{
+			name:        "malformed token",
+			status:      http.StatusNotFound,
+			description: "Not Found",
+			wantErr:     "create telegram client: invalid token: unexpected status 404: Not Found",
+		},
+		{
+			name:        "revoked token",
+			status:      http.StatusUnauthorized,
+			description: "Unauthorized",
+			wantErr:     "create telegram client: invalid token: unexpected status 401: Unauthorized",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/botbad-token/getMe", r.URL.Path)
+
+				w.WriteHeader(tt.status)
+				_, _ = fmt.Fprintf(w, `{"ok": false, "error_code": %d, "description": %q}`, tt.status, tt.description)
+			}))
+			t.Cleanup(srv.Close)
+
+			_, err := notifier.NewTelegram("bad-token", "123", notifier.WithAPIEndpoint(srv.URL+"/bot%s/%s"))
+			require.ErrorIs(t, err, notifier.ErrInvalidToken)
+			require.EqualError(t, err, tt.wantErr)
+
+			var derr *notifier.DeliveryError
+
+			require.ErrorAs(t, err, &derr)
+			assert.Equal(t, tt.status, derr.StatusCode)
+		})
+	}
+}
+
 func getEnv(tb testing.TB, key string) string {
 	tb.Helper()
 
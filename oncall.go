@@ -0,0 +1,303 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OnCallSchedule is a rotation of on-call members with overrides, e.g.
+//
+//	location: Europe/Berlin
+//	start: 2024-01-01
+//	handoff: "09:00"
+//	members:
+//	  - name: alice
+//	    destination: telegram://${TELEGRAM_TOKEN}@telegram?chats=111
+//	  - name: bob
+//	    destination: telegram://${TELEGRAM_TOKEN}@telegram?chats=222
+//	overrides:
+//	  - member: bob
+//	    start: 2024-01-10 09:00
+//	    end: 2024-01-11 09:00
+type OnCallSchedule struct {
+	// Location is the IANA time zone name of the schedule times. Empty means UTC.
+	Location string `yaml:"location" json:"location"`
+	// Start is the date the first member's shift starts, in 2006-01-02 format.
+	Start string `yaml:"start" json:"start"`
+	// Handoff is the time of day the shifts change, in 15:04 format. If empty, 09:00 is used.
+	Handoff string `yaml:"handoff" json:"handoff"`
+	// ShiftDays is the length of a shift in days. If zero, 7 (weekly rotation) is used.
+	ShiftDays int `yaml:"shift_days" json:"shift_days"`
+	// Members in the rotation order.
+	Members []OnCallMember `yaml:"members" json:"members"`
+	// Overrides replace the rotation for a time range, e.g. for vacations. Later overrides take precedence.
+	Overrides []OnCallOverride `yaml:"overrides" json:"overrides"`
+
+	loc       *time.Location
+	start     time.Time
+	overrides []onCallOverride
+}
+
+// OnCallMember is a member of the on-call rotation.
+type OnCallMember struct {
+	// Name of the member.
+	Name string `yaml:"name" json:"name"`
+	// Destination is the notifier URL of the member's personal channel, see FromURL.
+	// Environment variables in ${VAR} form are expanded.
+	Destination string `yaml:"destination" json:"destination"`
+}
+
+// OnCallOverride assigns the member to be on call for a time range.
+type OnCallOverride struct {
+	// Member is the name of the member on call.
+	Member string `yaml:"member" json:"member"`
+	// Start of the override, in 2006-01-02 15:04 format in the schedule location.
+	Start string `yaml:"start" json:"start"`
+	// End of the override, in 2006-01-02 15:04 format in the schedule location.
+	End string `yaml:"end" json:"end"`
+}
+
+type onCallOverride struct {
+	member     int
+	start, end time.Time
+}
+
+const (
+	defaultOnCallHandoff   = "09:00"
+	defaultOnCallShiftDays = 7
+	onCallTimeLayout       = "2006-01-02 15:04"
+)
+
+// LoadOnCallSchedule reads the YAML or JSON on-call schedule from the file.
+func LoadOnCallSchedule(path string) (*OnCallSchedule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read on-call schedule: %w", err)
+	}
+
+	return ParseOnCallSchedule(data)
+}
+
+// ParseOnCallSchedule parses and validates the YAML or JSON on-call schedule.
+func ParseOnCallSchedule(data []byte) (*OnCallSchedule, error) {
+	var s OnCallSchedule
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	if err := dec.Decode(&s); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("decode on-call schedule: %w", errors.Join(ErrInvalidConfig, err))
+	}
+
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// Validate checks the schedule and prepares it for OnCall. Environment variables in the
+// member destinations are expanded.
+func (s *OnCallSchedule) Validate() error {
+	if err := s.validate(); err != nil {
+		return fmt.Errorf("on-call schedule: %w", errors.Join(ErrInvalidConfig, err))
+	}
+
+	return nil
+}
+
+func (s *OnCallSchedule) validate() error {
+	if len(s.Members) == 0 {
+		return fmt.Errorf("members list is empty")
+	}
+
+	if s.Handoff == "" {
+		s.Handoff = defaultOnCallHandoff
+	}
+
+	if s.ShiftDays == 0 {
+		s.ShiftDays = defaultOnCallShiftDays
+	}
+
+	if s.ShiftDays < 0 {
+		return fmt.Errorf("shift days should be positive")
+	}
+
+	var err error
+
+	s.loc = time.UTC
+
+	if s.Location != "" {
+		if s.loc, err = time.LoadLocation(s.Location); err != nil {
+			return fmt.Errorf("location: %w", err)
+		}
+	}
+
+	if s.start, err = time.ParseInLocation(onCallTimeLayout, s.Start+" "+s.Handoff, s.loc); err != nil {
+		return fmt.Errorf("start '%s' and handoff '%s' should be in 2006-01-02 and 15:04 formats", s.Start, s.Handoff)
+	}
+
+	index := make(map[string]int, len(s.Members))
+
+	for i := range s.Members {
+		m := &s.Members[i]
+
+		if m.Name == "" {
+			return fmt.Errorf("member %d: name is empty", i+1)
+		}
+
+		if _, ok := index[m.Name]; ok {
+			return fmt.Errorf("member '%s' is duplicated", m.Name)
+		}
+
+		index[m.Name] = i
+
+		if m.Destination, err = expandEnv(m.Destination); err != nil {
+			return fmt.Errorf("member '%s': %w", m.Name, err)
+		}
+
+		if m.Destination == "" {
+			return fmt.Errorf("member '%s': destination is empty", m.Name)
+		}
+	}
+
+	s.overrides = make([]onCallOverride, 0, len(s.Overrides))
+
+	for i, o := range s.Overrides {
+		member, ok := index[o.Member]
+		if !ok {
+			return fmt.Errorf("override %d: unknown member '%s'", i+1, o.Member)
+		}
+
+		start, err := time.ParseInLocation(onCallTimeLayout, o.Start, s.loc)
+		if err != nil {
+			return fmt.Errorf("override %d: start should be in %s format", i+1, onCallTimeLayout)
+		}
+
+		end, err := time.ParseInLocation(onCallTimeLayout, o.End, s.loc)
+		if err != nil {
+			return fmt.Errorf("override %d: end should be in %s format", i+1, onCallTimeLayout)
+		}
+
+		if !end.After(start) {
+			return fmt.Errorf("override %d: end should be after start", i+1)
+		}
+
+		s.overrides = append(s.overrides, onCallOverride{member: member, start: start, end: end})
+	}
+
+	return nil
+}
+
+// OnCall returns the member on call at t. The schedule should be validated.
+func (s *OnCallSchedule) OnCall(t time.Time) (OnCallMember, error) {
+	if s.loc == nil {
+		return OnCallMember{}, fmt.Errorf("on-call schedule is not validated")
+	}
+
+	for i := len(s.overrides) - 1; i >= 0; i-- {
+		o := s.overrides[i]
+
+		if !t.Before(o.start) && t.Before(o.end) {
+			return s.Members[o.member], nil
+		}
+	}
+
+	if t.Before(s.start) {
+		return OnCallMember{}, fmt.Errorf("%s: %w", t.In(s.loc).Format(onCallTimeLayout), ErrNoOnCall)
+	}
+
+	// Count calendar days, so the handoff time is kept on DST changes.
+	lt := t.In(s.loc)
+	days := int(civilDate(lt).Sub(civilDate(s.start)).Hours() / 24)
+
+	if lt.Hour()*60+lt.Minute() < s.start.Hour()*60+s.start.Minute() {
+		days--
+	}
+
+	return s.Members[(days/s.ShiftDays)%len(s.Members)], nil
+}
+
+// civilDate returns the date of t as midnight UTC.
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// OnCallOptions are options for the notifier created by NewOnCallNotifier.
+type OnCallOptions struct {
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+}
+
+// onCallNotifier routes alerts to the member on call.
+type onCallNotifier struct {
+	schedule  *OnCallSchedule
+	notifiers map[string]Notifier
+	now       func() time.Time
+}
+
+// NewOnCallNotifier returns a new notifier that sends alerts to the personal destination of
+// the member currently on call. The member destinations are created with FromURL.
+// The member name is added to the metadata as the "on_call" field.
+func NewOnCallNotifier(schedule *OnCallSchedule, opts *OnCallOptions) (Notifier, error) {
+	if schedule == nil {
+		return nil, fmt.Errorf("on-call schedule is nil")
+	}
+
+	if schedule.loc == nil {
+		if err := schedule.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	n := &onCallNotifier{
+		schedule:  schedule,
+		notifiers: make(map[string]Notifier, len(schedule.Members)),
+		now:       time.Now,
+	}
+
+	if opts != nil && opts.Now != nil {
+		n.now = opts.Now
+	}
+
+	for _, m := range schedule.Members {
+		dest, err := FromURL(m.Destination)
+		if err != nil {
+			return nil, fmt.Errorf("on-call member '%s': %w", m.Name, err)
+		}
+
+		n.notifiers[m.Name] = dest
+	}
+
+	return n, nil
+}
+
+// Kind returns the notifier kind.
+func (n *onCallNotifier) Kind() string {
+	return "oncall"
+}
+
+// Alert sends the alert to the member on call.
+func (n *onCallNotifier) Alert(ctx context.Context, severity Severity, message string) error {
+	if err := validateAlert(severity, message); err != nil {
+		return err
+	}
+
+	m, err := n.schedule.OnCall(n.now())
+	if err != nil {
+		return err
+	}
+
+	if err = n.notifiers[m.Name].Alert(ContextWithField(ctx, "on_call", m.Name), severity, message); err != nil {
+		return fmt.Errorf("send alert to on-call member '%s': %w", m.Name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package notifier_test
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/notifier"
+)
+
+const onCallSchedule = `
+location: Europe/Berlin
+start: 2024-01-01
+handoff: "09:00"
+members:
+  - name: alice
+    destination: oncalltest://alice
+  - name: bob
+    destination: oncalltest://bob
+  - name: carol
+    destination: oncalltest://carol
+overrides:
+  - member: carol
+    start: 2024-01-03 12:00
+    end: 2024-01-04 12:00
+`
+
+func TestOnCallSchedule_OnCall(t *testing.T) {
+	s, err := notifier.ParseOnCallSchedule([]byte(onCallSchedule))
+	require.NoError(t, err)
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{at: time.Date(2024, time.January, 1, 9, 0, 0, 0, berlin), want: "alice"},
+		{at: time.Date(2024, time.January, 3, 12, 30, 0, 0, berlin), want: "carol"},
+		{at: time.Date(2024, time.January, 8, 8, 59, 0, 0, berlin), want: "alice"},
+		{at: time.Date(2024, time.January, 8, 9, 0, 0, 0, berlin), want: "bob"},
+		// 08:30 UTC is 09:30 in Berlin.
+		{at: time.Date(2024, time.January, 15, 8, 30, 0, 0, time.UTC), want: "carol"},
+		{at: time.Date(2024, time.January, 22, 9, 0, 0, 0, berlin), want: "alice"},
+		// The handoff time is kept after the DST change.
+		{at: time.Date(2024, time.April, 1, 8, 59, 0, 0, berlin), want: "alice"},
+		{at: time.Date(2024, time.April, 1, 9, 0, 0, 0, berlin), want: "bob"},
+	}
+
+	for _, tt := range tests {
+		m, err := s.OnCall(tt.at)
+		require.NoError(t, err)
+
+		assert.Equal(t, tt.want, m.Name, tt.at)
+	}
+
+	_, err = s.OnCall(time.Date(2023, time.December, 31, 0, 0, 0, 0, berlin))
+	require.ErrorIs(t, err, notifier.ErrNoOnCall)
+}
+
+func TestParseOnCallSchedule_Invalid(t *testing.T) {
+	override := "overrides: [{member: b, start: 2024-01-01 00:00, end: 2024-01-02 00:00}]"
+
+	for name, data := range map[string]string{
+		"no members":       "start: 2024-01-01",
+		"invalid start":    "start: 01.01.2024\nmembers: [{name: a, destination: 'x://'}]",
+		"empty dest":       "start: 2024-01-01\nmembers: [{name: a}]",
+		"duplicated":       "start: 2024-01-01\nmembers: [{name: a, destination: 'x://'}, {name: a, destination: 'x://'}]",
+		"unknown override": "start: 2024-01-01\nmembers: [{name: a, destination: 'x://'}]\n" + override,
+		"unknown field":    "start: 2024-01-01\nrotation: weekly",
+	} {
+		_, err := notifier.ParseOnCallSchedule([]byte(data))
+		require.ErrorIs(t, err, notifier.ErrInvalidConfig, name)
+	}
+}
+
+func TestOnCallNotifier(t *testing.T) {
+	buffers := map[string]*bytes.Buffer{}
+
+	notifier.Register("oncalltest", func(u *url.URL) (notifier.Notifier, error) {
+		buf := &bytes.Buffer{}
+		buffers[u.Host] = buf
+
+		return newTestNotifier(t, buf, u.Host), nil
+	})
+
+	path := filepath.Join(t.TempDir(), "oncall.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(onCallSchedule), 0o600))
+
+	s, err := notifier.LoadOnCallSchedule(path)
+	require.NoError(t, err)
+
+	now := time.Date(2024, time.January, 10, 12, 0, 0, 0, time.UTC)
+
+	n, err := notifier.NewOnCallNotifier(s, &notifier.OnCallOptions{
+		Now: func() time.Time { return now },
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, n.Alert(context.Background(), notifier.SeverityCritical, "database is down"))
+
+	assert.Empty(t, buffers["alice"].String())
+	assert.Contains(t, buffers["bob"].String(), "database is down")
+	assert.Contains(t, buffers["bob"].String(), "• on_call: bob")
+
+	require.ErrorIs(t, n.Alert(context.Background(), notifier.SeverityCritical, ""), notifier.ErrEmptyMessage)
+}
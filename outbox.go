@@ -0,0 +1,466 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// OutboxOptions are options for the notifier created by NewOutbox.
+type OutboxOptions struct {
+	// Path of the outbox log file. Required.
+	Path string
+	// MinBackoff is the delay before the first retry, doubled on every next retry. If zero, 1s is used.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximal delay between retries. If zero, 1m is used.
+	MaxBackoff time.Duration
+	// MaxAttempts is the number of delivery attempts after which the alert is dropped.
+	// If zero, the delivery is retried until it succeeds. Alerts failed with errors that are not
+	// retryable (see IsRetryable) are dropped immediately.
+	MaxAttempts int
+	// NoSync disables syncing the log to the disk after every write. It is faster, but alerts
+	// written right before a machine crash could be lost.
+	NoSync bool
+	// ErrorHandler is called when the delivery attempt fails. If nil, errors are ignored.
+	ErrorHandler func(err error)
+}
+
+const (
+	defaultOutboxMinBackoff = time.Second
+	defaultOutboxMaxBackoff = time.Minute
+	// outboxCompactThreshold is the number of log records after which the drained log is truncated.
+	outboxCompactThreshold = 1000
+)
+
+// Outbox log record operations.
+const (
+	outboxOpAdd  = "add"
+	outboxOpDone = "done"
+)
+
+// outboxRecord is a line of the outbox log.
+type outboxRecord struct {
+	Op       string    `json:"op"`
+	ID       uint64    `json:"id"`
+	Severity Severity  `json:"severity,omitempty"`
+	Message  string    `json:"message,omitempty"`
+	Metadata *Metadata `json:"metadata,omitempty"`
+	Time     time.Time `json:"time,omitzero"`
+}
+
+// Outbox is a notifier that persists alerts to an append-only log and delivers them in the background.
+type Outbox struct {
+	next Notifier
+	opts OutboxOptions
+
+	mu      sync.Mutex
+	file    *os.File
+	records int
+	nextID  uint64
+	queue   []outboxRecord
+	// drained is closed and replaced when the queue becomes empty.
+	drained chan struct{}
+
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+	// ctx of the deliveries, canceled by Close.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewOutbox returns a new notifier that appends every alert to the log file at opts.Path before
+// returning and delivers the alerts to the next notifier in order in the background, retrying failed
+// deliveries with exponential backoff, but no sooner than the RetryAfter requested by the destination.
+// Alerts left undelivered by a previous run are replayed on start.
+//
+// The alert metadata is persisted with the alert; other context values and cancellation are not.
+// Close should be called on shutdown.
+func NewOutbox(next Notifier, opts *OutboxOptions) (*Outbox, error) {
+	if next == nil {
+		return nil, fmt.Errorf("notifier is nil")
+	}
+
+	if opts == nil || opts.Path == "" {
+		return nil, fmt.Errorf("outbox path is empty")
+	}
+
+	o := &Outbox{
+		next:    next,
+		opts:    *opts,
+		drained: make(chan struct{}),
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	o.ctx, o.cancel = context.WithCancel(context.Background())
+
+	if o.opts.MinBackoff <= 0 {
+		o.opts.MinBackoff = defaultOutboxMinBackoff
+	}
+
+	if o.opts.MaxBackoff <= 0 {
+		o.opts.MaxBackoff = defaultOutboxMaxBackoff
+	}
+
+	if err := o.open(); err != nil {
+		o.cancel()
+
+		return nil, err
+	}
+
+	if len(o.queue) == 0 {
+		close(o.drained)
+	}
+
+	go o.run()
+
+	o.notify()
+
+	return o, nil
+}
+
+// Kind returns the notifier kind.
+func (o *Outbox) Kind() string {
+	return o.next.Kind()
+}
+
+// Alert persists the alert to the outbox log. The alert is delivered in the background.
+func (o *Outbox) Alert(ctx context.Context, severity Severity, message string) error {
+	if err := validateAlert(severity, message); err != nil {
+		return err
+	}
+
+	rec := outboxRecord{
+		Op:       outboxOpAdd,
+		Severity: severity,
+		Message:  message,
+		Time:     time.Now(),
+	}
+
+	if m, ok := MetadataFromContext(ctx); ok {
+		rec.Metadata = m
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.file == nil {
+		return ErrOutboxClosed
+	}
+
+	o.nextID++
+	rec.ID = o.nextID
+
+	if err := o.write(rec); err != nil {
+		return err
+	}
+
+	if len(o.queue) == 0 {
+		o.drained = make(chan struct{})
+	}
+
+	o.queue = append(o.queue, rec)
+
+	o.notify()
+
+	return nil
+}
+
+// Pending returns the number of undelivered alerts.
+func (o *Outbox) Pending() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return len(o.queue)
+}
+
+// Wait waits until all alerts are delivered or ctx is done.
+func (o *Outbox) Wait(ctx context.Context) error {
+	o.mu.Lock()
+	drained := o.drained
+	o.mu.Unlock()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops the delivery, canceling the context of the delivery in progress, and closes the log.
+// Undelivered alerts are kept in the log and replayed by the next NewOutbox.
+func (o *Outbox) Close() error {
+	o.mu.Lock()
+
+	if o.file == nil {
+		o.mu.Unlock()
+
+		return nil
+	}
+
+	close(o.stop)
+	o.cancel()
+
+	o.mu.Unlock()
+
+	<-o.done
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	err := o.file.Close()
+	o.file = nil
+
+	return err
+}
+
+// notify wakes up the delivery loop.
+func (o *Outbox) notify() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers the queued alerts until the outbox is closed.
+func (o *Outbox) run() {
+	defer close(o.done)
+
+	attempts := 0
+
+	for {
+		o.mu.Lock()
+
+		if len(o.queue) == 0 {
+			o.mu.Unlock()
+
+			select {
+			case <-o.stop:
+				return
+			case <-o.wake:
+				continue
+			}
+		}
+
+		rec := o.queue[0]
+
+		o.mu.Unlock()
+
+		err := o.deliver(rec)
+
+		if err != nil && o.ctx.Err() != nil {
+			// Interrupted by Close, the alert is replayed on the next start.
+			return
+		}
+
+		if err == nil || !IsRetryable(err) || (o.opts.MaxAttempts > 0 && attempts+1 >= o.opts.MaxAttempts) {
+			if err != nil && o.opts.ErrorHandler != nil {
+				o.opts.ErrorHandler(fmt.Errorf("drop alert %d after %d attempts: %w", rec.ID, attempts+1, err))
+			}
+
+			attempts = 0
+
+			if err = o.complete(rec.ID); err != nil && o.opts.ErrorHandler != nil {
+				o.opts.ErrorHandler(err)
+			}
+
+			continue
+		}
+
+		if o.opts.ErrorHandler != nil {
+			o.opts.ErrorHandler(fmt.Errorf("deliver alert %d: %w", rec.ID, err))
+		}
+
+		// The delay requested by the destination, e.g. on rate limiting, is the minimal backoff.
+		timer := time.NewTimer(max(o.backoff(attempts), RetryAfter(err)))
+		attempts++
+
+		select {
+		case <-o.stop:
+			timer.Stop()
+
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// deliver sends the alert to the next notifier.
+func (o *Outbox) deliver(rec outboxRecord) error {
+	ctx := o.ctx
+
+	if rec.Metadata != nil {
+		ctx = contextWithMetadata(ctx, *rec.Metadata)
+	}
+
+	return o.next.Alert(ctx, rec.Severity, rec.Message)
+}
+
+// backoff returns the delay before the retry after the given number of failed attempts.
+func (o *Outbox) backoff(attempts int) time.Duration {
+	d := o.opts.MinBackoff
+
+	for range attempts {
+		d *= 2
+
+		if d >= o.opts.MaxBackoff {
+			return o.opts.MaxBackoff
+		}
+	}
+
+	return d
+}
+
+// complete marks the alert delivered and removes it from the queue.
+func (o *Outbox) complete(id uint64) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.queue = o.queue[1:]
+
+	if len(o.queue) == 0 {
+		close(o.drained)
+
+		if o.records >= outboxCompactThreshold {
+			return o.truncate()
+		}
+	}
+
+	return o.write(outboxRecord{Op: outboxOpDone, ID: id})
+}
+
+// write appends the record to the log. Must be called with mu held.
+func (o *Outbox) write(rec outboxRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encode outbox record: %w", err)
+	}
+
+	if _, err = o.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write outbox: %w", err)
+	}
+
+	if !o.opts.NoSync {
+		if err = o.file.Sync(); err != nil {
+			return fmt.Errorf("sync outbox: %w", err)
+		}
+	}
+
+	o.records++
+
+	return nil
+}
+
+// truncate empties the drained log. Must be called with mu held.
+func (o *Outbox) truncate() error {
+	if err := o.file.Truncate(0); err != nil {
+		return fmt.Errorf("truncate outbox: %w", err)
+	}
+
+	if _, err := o.file.Seek(0, 0); err != nil {
+		return fmt.Errorf("truncate outbox: %w", err)
+	}
+
+	o.records = 0
+
+	return nil
+}
+
+// open replays the log and rewrites it with the undelivered alerts only.
+func (o *Outbox) open() error {
+	pending, err := readOutbox(o.opts.Path)
+	if err != nil {
+		return err
+	}
+
+	tmp := o.opts.Path + ".tmp"
+
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open outbox: %w", err)
+	}
+
+	o.file = f
+
+	for _, rec := range pending {
+		if err = o.write(rec); err != nil {
+			_ = f.Close()
+
+			return err
+		}
+
+		o.nextID = max(o.nextID, rec.ID)
+	}
+
+	if err = os.Rename(tmp, o.opts.Path); err != nil {
+		_ = f.Close()
+
+		return fmt.Errorf("open outbox: %w", err)
+	}
+
+	o.queue = pending
+
+	return nil
+}
+
+// readOutbox returns the undelivered alerts from the log in order. A missing log is empty.
+func readOutbox(path string) ([]outboxRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("read outbox: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var (
+		added []outboxRecord
+		done  = make(map[uint64]bool)
+	)
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 1<<24)
+
+	for sc.Scan() {
+		var rec outboxRecord
+
+		// A partially written last line after a crash is skipped.
+		if err = json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			continue
+		}
+
+		switch rec.Op {
+		case outboxOpAdd:
+			added = append(added, rec)
+		case outboxOpDone:
+			done[rec.ID] = true
+		}
+	}
+
+	if err = sc.Err(); err != nil {
+		return nil, fmt.Errorf("read outbox: %w", err)
+	}
+
+	pending := make([]outboxRecord, 0, len(added))
+
+	for _, rec := range added {
+		if !done[rec.ID] {
+			pending = append(pending, rec)
+		}
+	}
+
+	return pending, nil
+}
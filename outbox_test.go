@@ -0,0 +1,244 @@
+package notifier_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/notifier"
+)
+
+func TestOutbox(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.log")
+
+	var failures atomic.Int32
+
+	failures.Store(2)
+
+	n, alerts := newRecordingNotifier()
+
+	flaky := notifierFunc(func(ctx context.Context, severity notifier.Severity, message string) error {
+		if failures.Add(-1) >= 0 {
+			return errors.New("telegram is down")
+		}
+
+		return n.Alert(ctx, severity, message)
+	})
+
+	var deliveryErrors atomic.Int32
+
+	o, err := notifier.NewOutbox(flaky, &notifier.OutboxOptions{
+		Path:       path,
+		MinBackoff: time.Millisecond,
+		ErrorHandler: func(error) {
+			deliveryErrors.Add(1)
+		},
+	})
+	require.NoError(t, err)
+
+	ctx := notifier.ContextWithMetadata(context.Background(), notifier.Metadata{AppName: "api"})
+
+	require.NoError(t, o.Alert(ctx, notifier.SeverityCritical, "first"))
+	require.NoError(t, o.Alert(ctx, notifier.SeverityWarning, "second"))
+
+	ctxWait, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, o.Wait(ctxWait))
+
+	first := waitAlert(t, alerts)
+	assert.Equal(t, "first", first.message)
+	assert.Equal(t, notifier.SeverityCritical, first.severity)
+	assert.Equal(t, "api", first.metadata.AppName)
+	assert.Equal(t, "second", waitAlert(t, alerts).message)
+	assert.Equal(t, int32(2), deliveryErrors.Load())
+	assert.Zero(t, o.Pending())
+
+	require.NoError(t, o.Close())
+	require.ErrorIs(t, o.Alert(ctx, notifier.SeverityCritical, "late"), notifier.ErrOutboxClosed)
+}
+
+func TestOutbox_Replay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.log")
+
+	down := notifierFunc(func(context.Context, notifier.Severity, string) error {
+		return errors.New("telegram is down")
+	})
+
+	o, err := notifier.NewOutbox(down, &notifier.OutboxOptions{Path: path, MinBackoff: time.Hour})
+	require.NoError(t, err)
+
+	ctx := notifier.ContextWithMetadata(context.Background(), notifier.Metadata{AppName: "api"})
+
+	require.NoError(t, o.Alert(ctx, notifier.SeverityCritical, "first"))
+	require.NoError(t, o.Alert(ctx, notifier.SeverityWarning, "second"))
+	require.ErrorIs(t, o.Alert(ctx, notifier.SeverityWarning, ""), notifier.ErrEmptyMessage)
+
+	assert.Equal(t, 2, o.Pending())
+
+	// Simulate a restart.
+	require.NoError(t, o.Close())
+
+	n, alerts := newRecordingNotifier()
+
+	o, err = notifier.NewOutbox(n, &notifier.OutboxOptions{Path: path})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, o.Close())
+	})
+
+	first := waitAlert(t, alerts)
+	assert.Equal(t, "first", first.message)
+	assert.Equal(t, "api", first.metadata.AppName)
+	assert.Equal(t, "second", waitAlert(t, alerts).message)
+
+	require.NoError(t, o.Alert(ctx, notifier.SeverityInfo, "third"))
+	assert.Equal(t, "third", waitAlert(t, alerts).message)
+}
+
+func TestOutbox_MaxAttempts(t *testing.T) {
+	down := notifierFunc(func(context.Context, notifier.Severity, string) error {
+		return errors.New("telegram is down")
+	})
+
+	var dropped atomic.Value
+
+	o, err := notifier.NewOutbox(down, &notifier.OutboxOptions{
+		Path:        filepath.Join(t.TempDir(), "outbox.log"),
+		MinBackoff:  time.Millisecond,
+		MaxAttempts: 3,
+		NoSync:      true,
+		ErrorHandler: func(err error) {
+			dropped.Store(err.Error())
+		},
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, o.Close())
+	})
+
+	require.NoError(t, o.Alert(context.Background(), notifier.SeverityCritical, "message"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, o.Wait(ctx))
+	assert.Zero(t, o.Pending())
+	assert.Equal(t, "drop alert 1 after 3 attempts: telegram is down", dropped.Load())
+}
+
+func TestOutbox_CloseCancelsDelivery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.log")
+
+	started := make(chan struct{})
+
+	blocking := notifierFunc(func(ctx context.Context, _ notifier.Severity, _ string) error {
+		close(started)
+
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+
+	o, err := notifier.NewOutbox(blocking, &notifier.OutboxOptions{Path: path, MaxAttempts: 1})
+	require.NoError(t, err)
+
+	require.NoError(t, o.Alert(context.Background(), notifier.SeverityCritical, "database is down"))
+
+	<-started
+
+	closed := make(chan error, 1)
+
+	go func() {
+		closed <- o.Close()
+	}()
+
+	select {
+	case err = <-closed:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("close is blocked by the delivery")
+	}
+
+	// The interrupted alert is not dropped.
+	n, alerts := newRecordingNotifier()
+
+	o, err = notifier.NewOutbox(n, &notifier.OutboxOptions{Path: path})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, o.Close())
+	})
+
+	assert.Equal(t, "database is down", waitAlert(t, alerts).message)
+}
+
+func TestOutbox_DeliveryErrors(t *testing.T) {
+	var (
+		calls    atomic.Int32
+		attempts = make(chan time.Time, 10)
+	)
+
+	dest := notifierFunc(func(_ context.Context, _ notifier.Severity, message string) error {
+		attempts <- time.Now()
+
+		if message == "too large" {
+			return &notifier.DeliveryError{Kind: "test", StatusCode: http.StatusRequestEntityTooLarge}
+		}
+
+		if calls.Add(1) == 1 {
+			return &notifier.DeliveryError{
+				Kind:       "test",
+				StatusCode: http.StatusTooManyRequests,
+				Retryable:  true,
+				RetryAfter: 100 * time.Millisecond,
+			}
+		}
+
+		return nil
+	})
+
+	var dropped atomic.Value
+
+	o, err := notifier.NewOutbox(dest, &notifier.OutboxOptions{
+		Path:       filepath.Join(t.TempDir(), "outbox.log"),
+		MinBackoff: time.Millisecond,
+		NoSync:     true,
+		ErrorHandler: func(err error) {
+			if errors.Is(err, notifier.ErrPayloadTooLarge) {
+				dropped.Store(err.Error())
+			}
+		},
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, o.Close())
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// The alert that is not retryable is dropped after the first attempt.
+	require.NoError(t, o.Alert(ctx, notifier.SeverityCritical, "too large"))
+	require.NoError(t, o.Wait(ctx))
+	assert.Equal(t, "drop alert 1 after 1 attempts: unexpected status 413: Request Entity Too Large", dropped.Load())
+
+	<-attempts
+
+	// The rate limited alert is retried after RetryAfter.
+	require.NoError(t, o.Alert(ctx, notifier.SeverityCritical, "rate limited"))
+	require.NoError(t, o.Wait(ctx))
+
+	first, second := <-attempts, <-attempts
+	assert.GreaterOrEqual(t, second.Sub(first), 100*time.Millisecond)
+}
@@ -0,0 +1,85 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DeliveryPolicy decides whether an alert sent to several notifiers is delivered.
+type DeliveryPolicy struct {
+	// MinSuccess is the number of notifiers that should deliver the alert. Zero means all notifiers.
+	MinSuccess int
+	// BestEffort makes the alert always considered delivered, delivery errors are not returned.
+	BestEffort bool
+	// ContinueOnInvalid sends the alert to the remaining notifiers after a notifier rejected it
+	// with ErrEmptyMessage or ErrInvalidSeverity, instead of returning immediately.
+	ContinueOnInvalid bool
+}
+
+// DeliverAll returns the policy that requires all notifiers to deliver the alert.
+// The errors of all failed notifiers are joined.
+func DeliverAll() DeliveryPolicy {
+	return DeliveryPolicy{}
+}
+
+// DeliverAny returns the policy that requires at least one notifier to deliver the alert.
+func DeliverAny() DeliveryPolicy {
+	return DeliverAtLeast(1)
+}
+
+// DeliverAtLeast returns the policy that requires at least n notifiers to deliver the alert.
+func DeliverAtLeast(n int) DeliveryPolicy {
+	return DeliveryPolicy{
+		MinSuccess: n,
+	}
+}
+
+// DeliverBestEffort returns the policy that sends the alert to all notifiers and never returns delivery errors.
+// Invalid alerts are still rejected.
+func DeliverBestEffort() DeliveryPolicy {
+	return DeliveryPolicy{
+		BestEffort: true,
+	}
+}
+
+// ParseDeliveryPolicy returns the policy by its name: all, any, best_effort or at_least:N.
+func ParseDeliveryPolicy(s string) (DeliveryPolicy, error) {
+	switch s {
+	case "", "all":
+		return DeliverAll(), nil
+	case "any":
+		return DeliverAny(), nil
+	case "best_effort":
+		return DeliverBestEffort(), nil
+	}
+
+	if v, ok := strings.CutPrefix(s, "at_least:"); ok {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return DeliverAtLeast(n), nil
+		}
+	}
+
+	return DeliveryPolicy{}, fmt.Errorf("'%s', should be one of all, any, best_effort or at_least:N: %w",
+		s, ErrInvalidPolicy)
+}
+
+// result returns the error of the alert delivered by the given number of notifiers of total.
+// errs are the joined errors of the failed notifiers.
+func (p DeliveryPolicy) result(delivered, total int, errs error) error {
+	if p.BestEffort || errs == nil {
+		return nil
+	}
+
+	if p.MinSuccess == 0 {
+		return errs
+	}
+
+	if delivered >= p.MinSuccess {
+		return nil
+	}
+
+	return fmt.Errorf("delivered by %d of %d notifiers, %d required: %w",
+		delivered, total, p.MinSuccess, errors.Join(ErrQuorumNotReached, errs))
+}
@@ -0,0 +1,161 @@
+package notifier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/notifier"
+)
+
+func TestMultiNotifier_Policy(t *testing.T) {
+	ok := notifierFunc(func(context.Context, notifier.Severity, string) error {
+		return nil
+	})
+
+	failing := notifierFunc(func(context.Context, notifier.Severity, string) error {
+		return errors.New("unavailable")
+	})
+
+	tests := []struct {
+		name      string
+		policy    notifier.DeliveryPolicy
+		notifiers []notifier.Notifier
+		wantErr   error
+	}{
+		{
+			name:      "all delivered",
+			policy:    notifier.DeliverAll(),
+			notifiers: []notifier.Notifier{ok, ok},
+		},
+		{
+			name:      "all with failure",
+			policy:    notifier.DeliverAll(),
+			notifiers: []notifier.Notifier{ok, failing},
+			wantErr:   errors.New("send alert to 'func': unavailable"),
+		},
+		{
+			name:      "any delivered",
+			policy:    notifier.DeliverAny(),
+			notifiers: []notifier.Notifier{failing, ok, failing},
+		},
+		{
+			name:      "any failed",
+			policy:    notifier.DeliverAny(),
+			notifiers: []notifier.Notifier{failing, failing},
+			wantErr:   notifier.ErrQuorumNotReached,
+		},
+		{
+			name:      "at least reached",
+			policy:    notifier.DeliverAtLeast(2),
+			notifiers: []notifier.Notifier{ok, failing, ok},
+		},
+		{
+			name:      "at least not reached",
+			policy:    notifier.DeliverAtLeast(2),
+			notifiers: []notifier.Notifier{ok, failing, failing},
+			wantErr:   notifier.ErrQuorumNotReached,
+		},
+		{
+			name:      "best effort",
+			policy:    notifier.DeliverBestEffort(),
+			notifiers: []notifier.Notifier{failing, failing},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := notifier.NewMultiNotifierWithPolicy(tt.policy, tt.notifiers...)
+			require.NoError(t, err)
+
+			err = n.Alert(context.Background(), notifier.SeverityError, "message")
+
+			switch {
+			case tt.wantErr == nil:
+				require.NoError(t, err)
+			case errors.Is(tt.wantErr, notifier.ErrQuorumNotReached):
+				require.ErrorIs(t, err, notifier.ErrQuorumNotReached)
+				assert.ErrorContains(t, err, "unavailable")
+			default:
+				require.EqualError(t, err, tt.wantErr.Error())
+			}
+		})
+	}
+}
+
+func TestMultiNotifier_ContinueOnInvalid(t *testing.T) {
+	rejecting := notifierFunc(func(context.Context, notifier.Severity, string) error {
+		return notifier.ErrEmptyMessage
+	})
+
+	var delivered int
+
+	counting := notifierFunc(func(context.Context, notifier.Severity, string) error {
+		delivered++
+
+		return nil
+	})
+
+	n, err := notifier.NewMultiNotifier(rejecting, counting)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, n.Alert(context.Background(), notifier.SeverityInfo, "message"), notifier.ErrEmptyMessage)
+	assert.Equal(t, 0, delivered)
+
+	policy := notifier.DeliverAny()
+	policy.ContinueOnInvalid = true
+
+	n, err = notifier.NewMultiNotifierWithPolicy(policy, rejecting, counting)
+	require.NoError(t, err)
+
+	require.NoError(t, n.Alert(context.Background(), notifier.SeverityInfo, "message"))
+	assert.Equal(t, 1, delivered)
+}
+
+func TestNewMultiNotifierWithPolicy_Invalid(t *testing.T) {
+	ok := notifierFunc(func(context.Context, notifier.Severity, string) error {
+		return nil
+	})
+
+	_, err := notifier.NewMultiNotifierWithPolicy(notifier.DeliverAtLeast(3), ok, ok)
+	require.ErrorIs(t, err, notifier.ErrInvalidPolicy)
+
+	_, err = notifier.NewMultiNotifierWithPolicy(notifier.DeliverAny())
+	require.ErrorIs(t, err, notifier.ErrEmptyNotifiers)
+}
+
+func TestParseDeliveryPolicy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    notifier.DeliveryPolicy
+		wantErr bool
+	}{
+		{in: "", want: notifier.DeliverAll()},
+		{in: "all", want: notifier.DeliverAll()},
+		{in: "any", want: notifier.DeliverAny()},
+		{in: "best_effort", want: notifier.DeliverBestEffort()},
+		{in: "at_least:3", want: notifier.DeliverAtLeast(3)},
+		{in: "at_least:0", wantErr: true},
+		{in: "at_least:2abc", wantErr: true},
+		{in: "at_least:2 3", wantErr: true},
+		{in: "at_least:", wantErr: true},
+		{in: "most", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := notifier.ParseDeliveryPolicy(tt.in)
+			if tt.wantErr {
+				require.ErrorIs(t, err, notifier.ErrInvalidPolicy)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
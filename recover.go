@@ -0,0 +1,185 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxErrorAlertLength keeps error alerts below the Telegram message limit (4096 characters)
+	// leaving room for severity and metadata.
+	maxErrorAlertLength = 3000
+	// maxStackDepth limits the number of frames in the rendered stack trace.
+	maxStackDepth = 32
+	// maxErrorTreeDepth limits the depth of the rendered error chain.
+	maxErrorTreeDepth = 10
+)
+
+// Recover recovers from a panic and sends it as SeverityCritical alert with the stack trace
+// and the panic location. It must be deferred directly:
+//
+//	defer notifier.Recover(ctx, n)
+//
+// The panic is not propagated further, alert delivery errors are ignored.
+func Recover(ctx context.Context, n Notifier) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	err, ok := r.(error)
+	if !ok {
+		err = fmt.Errorf("%v", r)
+	}
+
+	frames := panicFrames(callers(1))
+
+	_ = n.Alert(errorAlertContext(ctx, frames), SeverityCritical, renderErrorAlert("panic", err, frames))
+}
+
+// AlertError sends the error as SeverityCritical alert.
+// The alert contains the error chain (including errors.Join trees), the stack trace of the caller,
+// the goroutine and the caller location. The message is trimmed to fit chat limits.
+func AlertError(ctx context.Context, n Notifier, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	frames := callers(1)
+
+	return n.Alert(errorAlertContext(ctx, frames), SeverityCritical, renderErrorAlert("error", err, frames))
+}
+
+// callers returns the stack frames of the caller, skipping skip frames above the caller of callers.
+func callers(skip int) []runtime.Frame {
+	pcs := make([]uintptr, maxStackDepth)
+
+	// Skip runtime.Callers and callers itself.
+	const ownFrames = 2
+
+	n := runtime.Callers(skip+ownFrames, pcs)
+
+	iter := runtime.CallersFrames(pcs[:n])
+
+	frames := make([]runtime.Frame, 0, n)
+
+	for {
+		frame, more := iter.Next()
+
+		frames = append(frames, frame)
+
+		if !more {
+			break
+		}
+	}
+
+	return frames
+}
+
+// panicFrames returns the frames starting from the function that panicked.
+func panicFrames(frames []runtime.Frame) []runtime.Frame {
+	for i, f := range frames {
+		if f.Function == "runtime.gopanic" && i+1 < len(frames) {
+			frames = frames[i+1:]
+
+			break
+		}
+	}
+
+	// Skip runtime frames, e.g. runtime.panicmem for nil pointer dereference.
+	for len(frames) > 1 && strings.HasPrefix(frames[0].Function, "runtime.") {
+		frames = frames[1:]
+	}
+
+	return frames
+}
+
+// errorAlertContext adds the goroutine and the caller location to the context metadata.
+func errorAlertContext(ctx context.Context, frames []runtime.Frame) context.Context {
+	md := Metadata{
+		Extra: map[string]string{
+			"goroutine": goroutineName(),
+		},
+	}
+
+	if len(frames) > 0 {
+		md.Extra["caller"] = fmt.Sprintf("%s:%d", frames[0].File, frames[0].Line)
+	}
+
+	return ContextWithMetadata(ctx, md)
+}
+
+// goroutineName returns the current goroutine name from the stack header, e.g. "goroutine 7".
+func goroutineName() string {
+	const headerSize = 64
+
+	buf := make([]byte, headerSize)
+	buf = buf[:runtime.Stack(buf, false)]
+
+	name, _, ok := bytes.Cut(buf, []byte(" ["))
+	if !ok {
+		return "unknown"
+	}
+
+	return string(name)
+}
+
+// renderErrorAlert renders the error chain and the stack trace.
+func renderErrorAlert(kind string, err error, frames []runtime.Frame) string {
+	sections := []string{kind + ": " + err.Error()}
+
+	if errors.Unwrap(err) != nil || isJoined(err) {
+		var sb strings.Builder
+
+		sb.WriteString("Error chain:\n")
+		writeErrorTree(&sb, err, 0)
+
+		sections = append(sections, strings.TrimSpace(sb.String()))
+	}
+
+	if len(frames) > 0 {
+		var sb strings.Builder
+
+		sb.WriteString("Stack:")
+
+		for _, f := range frames {
+			sb.WriteString("\n" + f.Function + "\n\t" + f.File + ":" + strconv.Itoa(f.Line))
+		}
+
+		sections = append(sections, sb.String())
+	}
+
+	return truncate(strings.Join(sections, "\n\n"), maxErrorAlertLength)
+}
+
+func isJoined(err error) bool {
+	_, ok := err.(interface{ Unwrap() []error })
+
+	return ok
+}
+
+// writeErrorTree writes the error and the errors it wraps as an indented tree.
+func writeErrorTree(sb *strings.Builder, err error, depth int) {
+	if err == nil || depth >= maxErrorTreeDepth {
+		return
+	}
+
+	sb.WriteString(strings.Repeat("  ", depth) + "- " + reflect.TypeOf(err).String() + ": " +
+		strings.ReplaceAll(err.Error(), "\n", "; ") + "\n")
+
+	//nolint:errorlint // Walking the tree requires type assertions on Unwrap methods.
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, child := range e.Unwrap() {
+			writeErrorTree(sb, child, depth+1)
+		}
+	case interface{ Unwrap() error }:
+		writeErrorTree(sb, e.Unwrap(), depth+1)
+	}
+}
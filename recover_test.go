@@ -0,0 +1,102 @@
+package notifier_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/notifier"
+)
+
+func panicking(ctx context.Context, n notifier.Notifier) {
+	defer notifier.Recover(ctx, n)
+
+	var m map[string]int
+
+	m["key"] = 1
+}
+
+func TestRecover(t *testing.T) {
+	var buf bytes.Buffer
+
+	n := newTestNotifier(t, &buf, "recover")
+
+	require.NotPanics(t, func() {
+		panicking(context.Background(), n)
+	})
+
+	got := buf.String()
+
+	for _, want := range []string{
+		"CRITICAL",
+		"panic: assignment to entry in nil map",
+		"\n\nStack:\ngithub.com/obalunenko/notifier_test.panicking",
+		"• caller: ",
+		"recover_test.go:",
+		"• goroutine: goroutine ",
+	} {
+		assert.Contains(t, got, want)
+	}
+}
+
+func TestRecover_NoPanic(t *testing.T) {
+	var buf bytes.Buffer
+
+	n := newTestNotifier(t, &buf, "recover")
+
+	func() {
+		defer notifier.Recover(context.Background(), n)
+	}()
+
+	assert.Empty(t, buf.String())
+}
+
+func TestAlertError(t *testing.T) {
+	var buf bytes.Buffer
+
+	n := newTestNotifier(t, &buf, "error")
+
+	require.NoError(t, notifier.AlertError(context.Background(), n, nil))
+	assert.Empty(t, buf.String())
+
+	err := fmt.Errorf("load config: %w", errors.Join(
+		fs.ErrNotExist,
+		fmt.Errorf("fallback: %w", fs.ErrPermission),
+	))
+
+	require.NoError(t, notifier.AlertError(context.Background(), n, err))
+
+	got := buf.String()
+
+	for _, want := range []string{
+		"CRITICAL",
+		"error: load config: file does not exist\nfallback: permission denied",
+		"\n\nError chain:\n- *fmt.wrapError: load config: file does not exist; fallback: permission denied",
+		"\n  - *errors.joinError: file does not exist; fallback: permission denied",
+		"\n    - *errors.errorString: file does not exist",
+		"\n    - *fmt.wrapError: fallback: permission denied",
+		"\n      - *errors.errorString: permission denied",
+		"\n\nStack:\ngithub.com/obalunenko/notifier_test.TestAlertError",
+		"recover_test.go:",
+	} {
+		assert.Contains(t, got, want)
+	}
+}
+
+func TestAlertError_Truncated(t *testing.T) {
+	var buf bytes.Buffer
+
+	n := newTestNotifier(t, &buf, "error")
+
+	require.NoError(t, notifier.AlertError(context.Background(), n, errors.New(strings.Repeat("x", 5000))))
+
+	assert.Less(t, len(buf.String()), 4096)
+	assert.Contains(t, buf.String(), "x…")
+}
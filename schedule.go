@@ -0,0 +1,222 @@
+package notifier
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule returns the activation times of a recurring job.
+type Schedule interface {
+	// Next returns the first activation time after t.
+	Next(t time.Time) time.Time
+}
+
+// cronSchedule is a schedule parsed from a cron expression.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny are set when the field is '*'. Following cron, when both day fields are
+	// restricted, a day matching either of them matches.
+	domAny, dowAny bool
+	loc            *time.Location
+}
+
+// cronField describes the range of a cron expression field.
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = [...]cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 6},
+}
+
+// cronShortcuts are the supported predefined schedules.
+var cronShortcuts = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+// ParseCron parses the standard 5-field cron expression "minute hour day-of-month month day-of-week",
+// e.g. "0 9 * * 1-5" for 09:00 on weekdays. Fields support '*', lists, ranges and steps ("*/15", "1-5/2"),
+// day of week 7 is Sunday. The @hourly, @daily, @weekly, @monthly and @yearly shortcuts are supported.
+// The schedule is evaluated in loc, nil means UTC.
+func ParseCron(expr string, loc *time.Location) (Schedule, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	if s, ok := cronShortcuts[strings.TrimSpace(expr)]; ok {
+		expr = s
+	}
+
+	parts := strings.Fields(expr)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("cron expression '%s' should have %d fields", expr, len(cronFields))
+	}
+
+	var bits [len(cronFields)]uint64
+
+	for i, part := range parts {
+		b, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron expression '%s': %w", expr, err)
+		}
+
+		bits[i] = b
+	}
+
+	// Sunday could be written as 7.
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	return &cronSchedule{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		domAny: parts[2] == "*",
+		dowAny: parts[4] == "*",
+		loc:    loc,
+	}, nil
+}
+
+// parseCronField returns the bit set of the values matched by the field.
+func parseCronField(s string, f cronField) (uint64, error) {
+	maxValue := f.max
+	if f.name == "day of week" {
+		maxValue = 7
+	}
+
+	var bits uint64
+
+	for _, item := range strings.Split(s, ",") {
+		rng, stepStr, hasStep := strings.Cut(item, "/")
+
+		step := 1
+
+		if hasStep {
+			v, err := strconv.Atoi(stepStr)
+			if err != nil || v <= 0 {
+				return 0, fmt.Errorf("%s: invalid step '%s'", f.name, stepStr)
+			}
+
+			step = v
+		}
+
+		lo, hi := f.min, maxValue
+
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			loStr, hiStr, _ := strings.Cut(rng, "-")
+
+			var err error
+
+			if lo, err = parseCronValue(loStr, f.min, maxValue); err != nil {
+				return 0, fmt.Errorf("%s: %w", f.name, err)
+			}
+
+			if hi, err = parseCronValue(hiStr, f.min, maxValue); err != nil {
+				return 0, fmt.Errorf("%s: %w", f.name, err)
+			}
+
+			if lo > hi {
+				return 0, fmt.Errorf("%s: invalid range '%s'", f.name, rng)
+			}
+		default:
+			v, err := parseCronValue(rng, f.min, maxValue)
+			if err != nil {
+				return 0, fmt.Errorf("%s: %w", f.name, err)
+			}
+
+			lo = v
+
+			if !hasStep {
+				hi = v
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseCronValue(s string, minValue, maxValue int) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < minValue || v > maxValue {
+		return 0, fmt.Errorf("value '%s' should be in range %d-%d", s, minValue, maxValue)
+	}
+
+	return v, nil
+}
+
+// maxCronLookahead bounds the search of the next activation, e.g. for "0 0 30 2 *".
+const maxCronLookahead = 5 * 366 * 24 * time.Hour
+
+// Next returns the first activation time after t, or zero time if there is none.
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.In(s.loc).Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxCronLookahead)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.loc)
+
+			continue
+		}
+
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.loc)
+
+			continue
+		}
+
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.loc)
+
+			continue
+		}
+
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
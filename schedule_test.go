@@ -0,0 +1,63 @@
+package notifier_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/notifier"
+)
+
+func TestParseCron(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// Wednesday.
+	from := time.Date(2024, time.January, 3, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		expr string
+		loc  *time.Location
+		want time.Time
+	}{
+		{name: "every 15 minutes", expr: "*/15 * * * *", want: time.Date(2024, time.January, 3, 10, 30, 0, 0, time.UTC)},
+		{name: "daily", expr: "@daily", want: time.Date(2024, time.January, 4, 0, 0, 0, 0, time.UTC)},
+		{name: "morning on weekdays", expr: "0 9 * * 1-5", want: time.Date(2024, time.January, 4, 9, 0, 0, 0, time.UTC)},
+		{name: "sunday as 7", expr: "30 8 * * 7", want: time.Date(2024, time.January, 7, 8, 30, 0, 0, time.UTC)},
+		{name: "list of hours", expr: "0 6,18 * * *", want: time.Date(2024, time.January, 3, 18, 0, 0, 0, time.UTC)},
+		{name: "monthly", expr: "@monthly", want: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "day of month or week", expr: "0 0 15 * 5", want: time.Date(2024, time.January, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "time zone", expr: "0 9 * * *", loc: berlin, want: time.Date(2024, time.January, 4, 9, 0, 0, 0, berlin)},
+		{name: "never", expr: "0 0 30 2 *", want: time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := notifier.ParseCron(tt.expr, tt.loc)
+			require.NoError(t, err)
+
+			assert.True(t, tt.want.Equal(s.Next(from)), "got %s, want %s", s.Next(from), tt.want)
+		})
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	} {
+		_, err := notifier.ParseCron(expr, nil)
+		assert.Error(t, err, expr)
+	}
+}
@@ -1,33 +1,193 @@
 package notifier
 
-//go:generate stringer -type Severity -output severity_string.go -trimprefix Severity -linecomment
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
 
 // Severity represents the severity of an alert.
+//
+// Severities are ordered by their value, built-in levels leave gaps for custom levels
+// added with RegisterSeverity.
+//
+// The numeric values changed when DEBUG, ERROR and EMERGENCY were added: INFO is 20 (was 1),
+// WARNING is 30 (was 2) and CRITICAL is 50 (was 3). Severities stored as numbers should be migrated;
+// store them by name (see MarshalText and ParseSeverity) to be independent of the values.
+// String is not generated by stringer anymore, since it has to include the registered levels.
 type Severity int
 
 const (
-	severityUnknown Severity = iota
+	severityUnknown Severity = 0
+	// SeverityDebug represents a debug alert.
+	SeverityDebug Severity = 10
 	// SeverityInfo represents an info alert.
-	SeverityInfo // INFO
+	SeverityInfo Severity = 20
 	// SeverityWarning represents a warning alert.
-	SeverityWarning // WARNING
+	SeverityWarning Severity = 30
+	// SeverityError represents an error alert.
+	SeverityError Severity = 40
 	// SeverityCritical represents a critical alert.
-	SeverityCritical // CRITICAL
+	SeverityCritical Severity = 50
+	// SeverityEmergency represents an emergency alert.
+	SeverityEmergency Severity = 60
+)
 
-	severitySentinel
+var (
+	severityMu    sync.RWMutex
+	severityNames = map[Severity]string{
+		SeverityDebug:     "DEBUG",
+		SeverityInfo:      "INFO",
+		SeverityWarning:   "WARNING",
+		SeverityError:     "ERROR",
+		SeverityCritical:  "CRITICAL",
+		SeverityEmergency: "EMERGENCY",
+	}
 )
 
+// RegisterSeverity registers a custom severity level with its name and emoji,
+// e.g. RegisterSeverity(35, "NOTICE", "📣").
+// Names are case-insensitive and must be unique. Registering an existing value replaces its name and emoji.
+func RegisterSeverity(value Severity, name, emoji string) error {
+	name = strings.ToUpper(strings.TrimSpace(name))
+
+	if value <= severityUnknown {
+		return fmt.Errorf("severity value %d should be positive: %w", int(value), ErrInvalidSeverity)
+	}
+
+	if name == "" {
+		return fmt.Errorf("severity name is empty: %w", ErrInvalidSeverity)
+	}
+
+	severityMu.Lock()
+	defer severityMu.Unlock()
+
+	for s, n := range severityNames {
+		if n == name && s != value {
+			return fmt.Errorf("severity '%s' is already registered with value %d: %w", name, int(s), ErrInvalidSeverity)
+		}
+	}
+
+	severityNames[value] = name
+	severityToEmoji[value] = emoji
+
+	return nil
+}
+
+// String returns the severity name.
+func (s Severity) String() string {
+	severityMu.RLock()
+	defer severityMu.RUnlock()
+
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+
+	return "Severity(" + strconv.Itoa(int(s)) + ")"
+}
+
 // Valid checks if the severity is valid.
 func (s Severity) Valid() bool {
-	return s > severityUnknown && s < severitySentinel
+	severityMu.RLock()
+	defer severityMu.RUnlock()
+
+	_, ok := severityNames[s]
+
+	return ok
+}
+
+// ParseSeverity returns the severity by its case-insensitive name.
+func ParseSeverity(s string) (Severity, error) {
+	name := strings.ToUpper(strings.TrimSpace(s))
+
+	severityMu.RLock()
+
+	for sev, n := range severityNames {
+		if n == name {
+			severityMu.RUnlock()
+
+			return sev, nil
+		}
+	}
+
+	severityMu.RUnlock()
+
+	return severityUnknown, fmt.Errorf("'%s', should be one of '%v': %w", s, allowedSeverities(), ErrInvalidSeverity)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Severity) MarshalText() ([]byte, error) {
+	if !s.Valid() {
+		return nil, fmt.Errorf("'%s', should be one of '%v': %w", s, allowedSeverities(), ErrInvalidSeverity)
+	}
+
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Severity) UnmarshalText(text []byte) error {
+	sev, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+
+	*s = sev
+
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Both names and numeric values are accepted.
+func (s *Severity) UnmarshalJSON(data []byte) error {
+	var v int
+
+	if err := json.Unmarshal(data, &v); err == nil {
+		sev := Severity(v)
+		if !sev.Valid() {
+			return fmt.Errorf("'%s', should be one of '%v': %w", sev, allowedSeverities(), ErrInvalidSeverity)
+		}
+
+		*s = sev
+
+		return nil
+	}
+
+	var name string
+
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("severity should be a string or a number: %w", ErrInvalidSeverity)
+	}
+
+	return s.UnmarshalText([]byte(name))
+}
+
+// Set implements flag.Value.
+func (s *Severity) Set(value string) error {
+	return s.UnmarshalText([]byte(value))
 }
 
-var allowedSeverities = func() []string {
-	allowed := make([]string, 0, severitySentinel)
+// allowedSeverities returns the names of the registered severities ordered by value.
+func allowedSeverities() []string {
+	severityMu.RLock()
+	defer severityMu.RUnlock()
+
+	values := make([]Severity, 0, len(severityNames))
 
-	for i := SeverityInfo; i < severitySentinel; i++ {
-		allowed = append(allowed, i.String())
+	for s := range severityNames {
+		values = append(values, s)
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return values[i] < values[j]
+	})
+
+	allowed := make([]string, 0, len(values))
+
+	for _, s := range values {
+		allowed = append(allowed, severityNames[s])
 	}
 
 	return allowed
-}()
+}
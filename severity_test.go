@@ -0,0 +1,105 @@
+package notifier
+
+import (
+	"encoding/json"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    Severity
+		wantErr error
+	}{
+		{name: "upper case", in: "ERROR", want: SeverityError},
+		{name: "lower case", in: "emergency", want: SeverityEmergency},
+		{name: "spaces", in: " debug ", want: SeverityDebug},
+		{name: "unknown", in: "fatal", want: severityUnknown, wantErr: ErrInvalidSeverity},
+		{name: "empty", in: "", want: severityUnknown, wantErr: ErrInvalidSeverity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSeverity(tt.in)
+			require.ErrorIs(t, err, tt.wantErr)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSeverity_Ordering(t *testing.T) {
+	assert.True(t, SeverityDebug < SeverityInfo)
+	assert.True(t, SeverityInfo < SeverityWarning)
+	assert.True(t, SeverityWarning < SeverityError)
+	assert.True(t, SeverityError < SeverityCritical)
+	assert.True(t, SeverityCritical < SeverityEmergency)
+}
+
+func TestSeverity_JSON(t *testing.T) {
+	type payload struct {
+		Severity Severity `json:"severity"`
+	}
+
+	b, err := json.Marshal(payload{Severity: SeverityCritical})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"severity":"CRITICAL"}`, string(b))
+
+	_, err = json.Marshal(payload{Severity: Severity(100)})
+	require.ErrorIs(t, err, ErrInvalidSeverity)
+
+	var p payload
+
+	require.NoError(t, json.Unmarshal([]byte(`{"severity":"warning"}`), &p))
+	assert.Equal(t, SeverityWarning, p.Severity)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"severity":40}`), &p))
+	assert.Equal(t, SeverityError, p.Severity)
+
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"severity":"fatal"}`), &p), ErrInvalidSeverity)
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"severity":100}`), &p), ErrInvalidSeverity)
+}
+
+func TestSeverity_Flag(t *testing.T) {
+	sev := SeverityWarning
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&sev, "severity", "alert severity")
+
+	require.NoError(t, fs.Parse([]string{"-severity", "info"}))
+	assert.Equal(t, SeverityInfo, sev)
+
+	require.Error(t, fs.Parse([]string{"-severity", "fatal"}))
+}
+
+func TestRegisterSeverity(t *testing.T) {
+	const notice Severity = 35
+
+	t.Cleanup(func() {
+		severityMu.Lock()
+		defer severityMu.Unlock()
+
+		delete(severityNames, notice)
+		delete(severityToEmoji, notice)
+	})
+
+	require.NoError(t, RegisterSeverity(notice, "notice", "📣"))
+
+	assert.True(t, notice.Valid())
+	assert.Equal(t, "NOTICE", notice.String())
+	assert.Equal(t, "📣", severityEmoji(notice))
+	assert.Equal(t, []string{"DEBUG", "INFO", "WARNING", "NOTICE", "ERROR", "CRITICAL", "EMERGENCY"}, allowedSeverities())
+
+	got, err := ParseSeverity("Notice")
+	require.NoError(t, err)
+	assert.Equal(t, notice, got)
+
+	require.ErrorIs(t, RegisterSeverity(36, "INFO", ""), ErrInvalidSeverity)
+	require.ErrorIs(t, RegisterSeverity(0, "ZERO", ""), ErrInvalidSeverity)
+	require.ErrorIs(t, RegisterSeverity(37, " ", ""), ErrInvalidSeverity)
+}
@@ -0,0 +1,475 @@
+package notifier
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Silence mutes the alerts matching all of its matchers while it is active.
+type Silence struct {
+	// ID of the silence. Generated by Silencer.Add if empty.
+	ID string `json:"id"`
+	// Comment describes the reason of the silence, e.g. "nightly deploy".
+	Comment string `json:"comment,omitempty"`
+	// Severities the silence matches. Empty matches any severity.
+	Severities []Severity `json:"severities,omitempty"`
+	// Metadata values by field key the silence matches, e.g. "app_name": "api".
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// MessagePattern is a regular expression the alert message should match. Empty matches any message.
+	MessagePattern string `json:"message_pattern,omitempty"`
+	// StartsAt is the start of the silence. Zero means that it starts immediately.
+	StartsAt time.Time `json:"starts_at,omitzero"`
+	// EndsAt is the end of the silence. Zero means that it never ends.
+	EndsAt time.Time `json:"ends_at,omitzero"`
+	// Window limits the silence to a recurring time window within StartsAt and EndsAt.
+	Window *RecurringWindow `json:"window,omitempty"`
+
+	message *regexp.Regexp
+}
+
+// RecurringWindow is a time window repeated on the given weekdays, e.g. 02:00-03:00 on weekdays.
+// A window ending before its start, e.g. 23:00-01:00, spans midnight.
+type RecurringWindow struct {
+	// Start is the time of day the window starts, in 15:04 format.
+	Start string `json:"start"`
+	// End is the time of day the window ends, in 15:04 format.
+	End string `json:"end"`
+	// Weekdays the window starts on. Empty means every day.
+	Weekdays []time.Weekday `json:"weekdays,omitempty"`
+	// Location is the IANA time zone name, e.g. "Europe/Berlin". Empty means UTC.
+	Location string `json:"location,omitempty"`
+
+	start, end time.Duration
+	loc        *time.Location
+}
+
+// validate parses the window.
+func (w *RecurringWindow) validate() error {
+	var err error
+
+	if w.start, err = parseTimeOfDay(w.Start); err != nil {
+		return fmt.Errorf("window start: %w", err)
+	}
+
+	if w.end, err = parseTimeOfDay(w.End); err != nil {
+		return fmt.Errorf("window end: %w", err)
+	}
+
+	if w.start == w.end {
+		return fmt.Errorf("window start and end are equal")
+	}
+
+	w.loc = time.UTC
+
+	if w.Location != "" {
+		if w.loc, err = time.LoadLocation(w.Location); err != nil {
+			return fmt.Errorf("window location: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// active checks if t is within the window. The window should be validated.
+func (w *RecurringWindow) active(t time.Time) bool {
+	t = t.In(w.loc)
+
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, w.loc)
+	sinceMidnight := t.Sub(day)
+
+	if w.start < w.end {
+		return sinceMidnight >= w.start && sinceMidnight < w.end && w.onDay(day.Weekday())
+	}
+
+	// The window spans midnight: it is either started today or yesterday.
+	if sinceMidnight >= w.start {
+		return w.onDay(day.Weekday())
+	}
+
+	return sinceMidnight < w.end && w.onDay(day.AddDate(0, 0, -1).Weekday())
+}
+
+func (w *RecurringWindow) onDay(d time.Weekday) bool {
+	return len(w.Weekdays) == 0 || slices.Contains(w.Weekdays, d)
+}
+
+// parseTimeOfDay returns the duration since midnight of the time of day in 15:04 format.
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day '%s', should be in HH:MM format", s)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// validate compiles the silence matchers.
+func (s *Silence) validate() error {
+	for _, sev := range s.Severities {
+		if !sev.Valid() {
+			return fmt.Errorf("'%s', should be one of '%v': %w", sev, allowedSeverities(), ErrInvalidSeverity)
+		}
+	}
+
+	if s.MessagePattern != "" {
+		re, err := regexp.Compile(s.MessagePattern)
+		if err != nil {
+			return fmt.Errorf("message pattern: %w", err)
+		}
+
+		s.message = re
+	}
+
+	if !s.StartsAt.IsZero() && !s.EndsAt.IsZero() && !s.EndsAt.After(s.StartsAt) {
+		return fmt.Errorf("ends at should be after starts at")
+	}
+
+	if s.Window != nil {
+		if err := s.Window.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// active checks if the silence is active at t. The silence should be validated.
+func (s *Silence) active(t time.Time) bool {
+	if !s.StartsAt.IsZero() && t.Before(s.StartsAt) {
+		return false
+	}
+
+	if !s.EndsAt.IsZero() && !t.Before(s.EndsAt) {
+		return false
+	}
+
+	return s.Window == nil || s.Window.active(t)
+}
+
+// expired checks if the silence could not be active at t or later.
+func (s *Silence) expired(t time.Time) bool {
+	return !s.EndsAt.IsZero() && !t.Before(s.EndsAt)
+}
+
+// matches checks if the alert matches the silence.
+func (s *Silence) matches(severity Severity, message string, metadata map[string]string) bool {
+	if len(s.Severities) > 0 && !slices.Contains(s.Severities, severity) {
+		return false
+	}
+
+	for k, v := range s.Metadata {
+		if metadata[k] != v {
+			return false
+		}
+	}
+
+	return s.message == nil || s.message.MatchString(message)
+}
+
+// SuppressedAlert is an alert muted by a silence.
+type SuppressedAlert struct {
+	SilenceID string
+	Severity  Severity
+	Message   string
+	Metadata  map[string]string
+	Time      time.Time
+}
+
+// SilencerOptions are options for the notifier created by NewSilencer.
+type SilencerOptions struct {
+	// Path of the JSON file the silences are persisted to. The silences are loaded from it on creation.
+	// If empty, the silences are kept in memory only.
+	Path string
+	// MaxSuppressed is the number of the latest suppressed alerts kept for the report.
+	// If zero, 1000 alerts are kept.
+	MaxSuppressed int
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+}
+
+const defaultMaxSuppressed = 1000
+
+// Silencer is a notifier that drops the alerts matching active silences.
+type Silencer struct {
+	next Notifier
+	opts SilencerOptions
+
+	mu         sync.Mutex
+	silences   []*Silence
+	suppressed []SuppressedAlert
+}
+
+// NewSilencer returns a new notifier that drops the alerts matching any active silence and
+// passes others to the next notifier. Silences could be added and removed at runtime,
+// e.g. to mute expected alerts during planned maintenance.
+func NewSilencer(next Notifier, opts *SilencerOptions) (*Silencer, error) {
+	if next == nil {
+		return nil, fmt.Errorf("notifier is nil")
+	}
+
+	s := &Silencer{
+		next: next,
+	}
+
+	if opts != nil {
+		s.opts = *opts
+	}
+
+	if s.opts.MaxSuppressed <= 0 {
+		s.opts.MaxSuppressed = defaultMaxSuppressed
+	}
+
+	if s.opts.Now == nil {
+		s.opts.Now = time.Now
+	}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Kind returns the notifier kind.
+func (s *Silencer) Kind() string {
+	return s.next.Kind()
+}
+
+// Alert sends the alert unless it is muted by an active silence.
+func (s *Silencer) Alert(ctx context.Context, severity Severity, message string) error {
+	if err := validateAlert(severity, message); err != nil {
+		return err
+	}
+
+	var metadata map[string]string
+
+	if m, ok := MetadataFromContext(ctx); ok {
+		metadata = fieldsMap(m.fields())
+	}
+
+	now := s.opts.Now()
+
+	s.mu.Lock()
+
+	for _, silence := range s.silences {
+		if silence.active(now) && silence.matches(severity, message, metadata) {
+			s.suppress(SuppressedAlert{
+				SilenceID: silence.ID,
+				Severity:  severity,
+				Message:   message,
+				Metadata:  metadata,
+				Time:      now,
+			})
+
+			s.mu.Unlock()
+
+			return nil
+		}
+	}
+
+	s.mu.Unlock()
+
+	return s.next.Alert(ctx, severity, message)
+}
+
+// suppress records the suppressed alert. Must be called with mu held.
+func (s *Silencer) suppress(a SuppressedAlert) {
+	if len(s.suppressed) >= s.opts.MaxSuppressed {
+		s.suppressed = slices.Delete(s.suppressed, 0, len(s.suppressed)-s.opts.MaxSuppressed+1)
+	}
+
+	s.suppressed = append(s.suppressed, a)
+}
+
+// Add adds the silence and returns its ID. Expired silences are removed.
+func (s *Silencer) Add(silence Silence) (string, error) {
+	// The silence is copied, so the caller could reuse it without affecting the silencer.
+	silence = silence.clone()
+
+	if err := silence.validate(); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidSilence, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if silence.ID == "" {
+		silence.ID = newSilenceID()
+	}
+
+	now := s.opts.Now()
+
+	silences := make([]*Silence, 0, len(s.silences)+1)
+
+	for _, cur := range s.silences {
+		if cur.ID != silence.ID && !cur.expired(now) {
+			silences = append(silences, cur)
+		}
+	}
+
+	silences = append(silences, &silence)
+
+	if err := s.save(silences); err != nil {
+		return "", err
+	}
+
+	s.silences = silences
+
+	return silence.ID, nil
+}
+
+// Remove removes the silence by its ID.
+func (s *Silencer) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.silences, func(silence *Silence) bool {
+		return silence.ID == id
+	})
+	if i < 0 {
+		return fmt.Errorf("'%s': %w", id, ErrSilenceNotFound)
+	}
+
+	silences := slices.Delete(slices.Clone(s.silences), i, i+1)
+
+	if err := s.save(silences); err != nil {
+		return err
+	}
+
+	s.silences = silences
+
+	return nil
+}
+
+// Silences returns the current silences.
+func (s *Silencer) Silences() []Silence {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]Silence, 0, len(s.silences))
+
+	for _, silence := range s.silences {
+		res = append(res, silence.clone())
+	}
+
+	return res
+}
+
+// clone returns a deep copy of the silence, so it could be changed without affecting the silencer.
+func (s *Silence) clone() Silence {
+	c := *s
+
+	c.Severities = slices.Clone(s.Severities)
+	c.Metadata = maps.Clone(s.Metadata)
+
+	if s.Window != nil {
+		w := *s.Window
+		w.Weekdays = slices.Clone(s.Window.Weekdays)
+		c.Window = &w
+	}
+
+	return c
+}
+
+// Report returns the suppressed alerts, oldest first. If reset is true, the report is cleared.
+func (s *Silencer) Report(reset bool) []SuppressedAlert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := slices.Clone(s.suppressed)
+
+	if reset {
+		s.suppressed = nil
+	}
+
+	return res
+}
+
+// load reads the silences from the file, a missing file is not an error.
+func (s *Silencer) load() error {
+	if s.opts.Path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.opts.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("read silences: %w", err)
+	}
+
+	var silences []*Silence
+
+	if err = json.Unmarshal(data, &silences); err != nil {
+		return fmt.Errorf("decode silences: %w", err)
+	}
+
+	for _, silence := range silences {
+		if err = silence.validate(); err != nil {
+			return fmt.Errorf("silence '%s': %w: %w", silence.ID, ErrInvalidSilence, err)
+		}
+	}
+
+	s.silences = silences
+
+	return nil
+}
+
+// save writes the silences to the file atomically.
+func (s *Silencer) save(silences []*Silence) error {
+	if s.opts.Path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(silences, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode silences: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.opts.Path), filepath.Base(s.opts.Path)+".*")
+	if err != nil {
+		return fmt.Errorf("save silences: %w", err)
+	}
+
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("save silences: %w", err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("save silences: %w", err)
+	}
+
+	if err = os.Rename(tmp.Name(), s.opts.Path); err != nil {
+		return fmt.Errorf("save silences: %w", err)
+	}
+
+	return nil
+}
+
+// newSilenceID returns a random silence ID.
+func newSilenceID() string {
+	b := make([]byte, 8)
+
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
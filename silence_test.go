@@ -0,0 +1,203 @@
+package notifier_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/notifier"
+)
+
+func TestSilencer(t *testing.T) {
+	var buf bytes.Buffer
+
+	now := time.Date(2024, time.January, 3, 2, 30, 0, 0, time.UTC) // Wednesday.
+
+	s, err := notifier.NewSilencer(newTestNotifier(t, &buf, "silence"), &notifier.SilencerOptions{
+		Now: func() time.Time { return now },
+	})
+	require.NoError(t, err)
+
+	id, err := s.Add(notifier.Silence{
+		Comment:        "nightly deploy",
+		Severities:     []notifier.Severity{notifier.SeverityWarning},
+		Metadata:       map[string]string{"app_name": "api"},
+		MessagePattern: "^deploy",
+		Window:         &notifier.RecurringWindow{Start: "23:00", End: "03:00"},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+
+	apiCtx := notifier.ContextWithMetadata(context.Background(), notifier.Metadata{AppName: "api"})
+
+	require.NoError(t, s.Alert(apiCtx, notifier.SeverityWarning, "deploy started"))
+	assert.Empty(t, buf.String())
+
+	require.NoError(t, s.Alert(apiCtx, notifier.SeverityCritical, "deploy failed"))
+	assert.Contains(t, buf.String(), "deploy failed")
+
+	buf.Reset()
+
+	require.NoError(t, s.Alert(context.Background(), notifier.SeverityWarning, "deploy started"))
+	assert.Contains(t, buf.String(), "deploy started")
+
+	buf.Reset()
+
+	now = now.Add(time.Hour) // 03:30, out of the window.
+
+	require.NoError(t, s.Alert(apiCtx, notifier.SeverityWarning, "deploy started"))
+	assert.Contains(t, buf.String(), "deploy started")
+
+	report := s.Report(true)
+	require.Len(t, report, 1)
+	assert.Equal(t, id, report[0].SilenceID)
+	assert.Equal(t, "deploy started", report[0].Message)
+	assert.Equal(t, "api", report[0].Metadata["app_name"])
+	assert.Empty(t, s.Report(false))
+
+	require.NoError(t, s.Remove(id))
+	require.ErrorIs(t, s.Remove(id), notifier.ErrSilenceNotFound)
+	require.ErrorIs(t, s.Alert(apiCtx, notifier.SeverityWarning, ""), notifier.ErrEmptyMessage)
+}
+
+func TestSilencer_TimeRange(t *testing.T) {
+	var buf bytes.Buffer
+
+	now := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	s, err := notifier.NewSilencer(newTestNotifier(t, &buf, "silence"), &notifier.SilencerOptions{
+		Now: func() time.Time { return now },
+	})
+	require.NoError(t, err)
+
+	_, err = s.Add(notifier.Silence{
+		StartsAt: now.Add(time.Hour),
+		EndsAt:   now.Add(2 * time.Hour),
+	})
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		at     time.Time
+		silent bool
+	}{
+		{at: now, silent: false},
+		{at: now.Add(time.Hour), silent: true},
+		{at: now.Add(2 * time.Hour), silent: false},
+	} {
+		buf.Reset()
+		now = tt.at
+
+		require.NoError(t, s.Alert(context.Background(), notifier.SeverityInfo, "message"))
+		assert.Equal(t, tt.silent, buf.Len() == 0, tt.at)
+	}
+
+	// Expired silences are removed on add.
+	_, err = s.Add(notifier.Silence{MessagePattern: "other"})
+	require.NoError(t, err)
+	assert.Len(t, s.Silences(), 1)
+}
+
+func TestSilencer_Persistence(t *testing.T) {
+	var buf bytes.Buffer
+
+	path := filepath.Join(t.TempDir(), "silences.json")
+
+	s, err := notifier.NewSilencer(newTestNotifier(t, &buf, "silence"), &notifier.SilencerOptions{Path: path})
+	require.NoError(t, err)
+
+	id, err := s.Add(notifier.Silence{
+		ID:         "maintenance",
+		Severities: []notifier.Severity{notifier.SeverityInfo},
+		Window: &notifier.RecurringWindow{
+			Start:    "00:00",
+			End:      "23:59",
+			Weekdays: []time.Weekday{time.Saturday, time.Sunday},
+			Location: "Europe/Berlin",
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "maintenance", id)
+
+	loaded, err := notifier.NewSilencer(newTestNotifier(t, &buf, "silence"), &notifier.SilencerOptions{Path: path})
+	require.NoError(t, err)
+
+	silences := loaded.Silences()
+	require.Len(t, silences, 1)
+	assert.Equal(t, "maintenance", silences[0].ID)
+	assert.Equal(t, []notifier.Severity{notifier.SeverityInfo}, silences[0].Severities)
+	assert.Equal(t, "Europe/Berlin", silences[0].Window.Location)
+
+	// Zero times are not persisted.
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "starts_at")
+	assert.NotContains(t, string(data), "ends_at")
+
+	// Changing the returned silences does not change the silencer.
+	silences[0].Window.Weekdays[0] = time.Monday
+	silences[0].Window.Start = "12:00"
+	silences[0].Severities[0] = notifier.SeverityCritical
+
+	silences = loaded.Silences()
+	assert.Equal(t, []time.Weekday{time.Saturday, time.Sunday}, silences[0].Window.Weekdays)
+	assert.Equal(t, "00:00", silences[0].Window.Start)
+	assert.Equal(t, []notifier.Severity{notifier.SeverityInfo}, silences[0].Severities)
+}
+
+func TestSilencer_AddCopiesSilence(t *testing.T) {
+	var buf bytes.Buffer
+
+	now := time.Date(2024, time.January, 3, 2, 30, 0, 0, time.UTC)
+
+	s, err := notifier.NewSilencer(newTestNotifier(t, &buf, "silence"), &notifier.SilencerOptions{
+		Now: func() time.Time { return now },
+	})
+	require.NoError(t, err)
+
+	silence := notifier.Silence{
+		Severities: []notifier.Severity{notifier.SeverityWarning},
+		Metadata:   map[string]string{"app_name": "api"},
+		Window:     &notifier.RecurringWindow{Start: "23:00", End: "03:00"},
+	}
+
+	_, err = s.Add(silence)
+	require.NoError(t, err)
+
+	// Changing the added silence does not change the silencer.
+	silence.Severities[0] = notifier.SeverityCritical
+	silence.Metadata["app_name"] = "worker"
+	silence.Window.Start = "12:00"
+
+	silences := s.Silences()
+	require.Len(t, silences, 1)
+	assert.Equal(t, []notifier.Severity{notifier.SeverityWarning}, silences[0].Severities)
+	assert.Equal(t, map[string]string{"app_name": "api"}, silences[0].Metadata)
+	assert.Equal(t, "23:00", silences[0].Window.Start)
+
+	apiCtx := notifier.ContextWithMetadata(context.Background(), notifier.Metadata{AppName: "api"})
+
+	require.NoError(t, s.Alert(apiCtx, notifier.SeverityWarning, "deploy started"))
+	assert.Empty(t, buf.String())
+}
+
+func TestSilencer_InvalidSilence(t *testing.T) {
+	s, err := notifier.NewSilencer(newTestNotifier(t, &bytes.Buffer{}, "silence"), nil)
+	require.NoError(t, err)
+
+	for _, silence := range []notifier.Silence{
+		{MessagePattern: "("},
+		{Severities: []notifier.Severity{notifier.Severity(100)}},
+		{Window: &notifier.RecurringWindow{Start: "25:00", End: "01:00"}},
+		{Window: &notifier.RecurringWindow{Start: "01:00", End: "01:00"}},
+		{StartsAt: time.Now(), EndsAt: time.Now().Add(-time.Hour)},
+	} {
+		_, err = s.Add(silence)
+		require.ErrorIs(t, err, notifier.ErrInvalidSilence)
+	}
+}
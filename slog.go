@@ -0,0 +1,188 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// SlogHandlerOptions are options for the slog handler created by NewSlogHandler.
+type SlogHandlerOptions struct {
+	// Level is the minimum level of records that raise alerts.
+	// If nil, slog.LevelError is used.
+	Level slog.Leveler
+}
+
+// slogHandler passes records to the wrapped handler and raises alerts for records at or above the level.
+type slogHandler struct {
+	next     slog.Handler
+	notifier Notifier
+	level    slog.Leveler
+	// attrs are the flattened attributes added by WithAttrs.
+	attrs []slog.Attr
+	// group is the dot separated group prefix added by WithGroup.
+	group string
+}
+
+// NewSlogHandler returns a new slog.Handler that passes all records to the next handler
+// and sends records at or above the configured level to the notifier.
+//
+// Levels are mapped to Severity: custom levels from slog.LevelError+4 to SeverityCritical,
+// slog.LevelError to SeverityError, slog.LevelWarn to SeverityWarning, slog.LevelInfo to SeverityInfo
+// and lower levels to SeverityDebug.
+// Record attributes are added to the Metadata extra fields, groups are joined with dots.
+func NewSlogHandler(next slog.Handler, n Notifier, opts *SlogHandlerOptions) (slog.Handler, error) {
+	if next == nil {
+		return nil, fmt.Errorf("slog handler is nil")
+	}
+
+	if n == nil {
+		return nil, fmt.Errorf("notifier is nil")
+	}
+
+	var level slog.Leveler = slog.LevelError
+
+	if opts != nil && opts.Level != nil {
+		level = opts.Level
+	}
+
+	return &slogHandler{
+		next:     next,
+		notifier: n,
+		level:    level,
+		attrs:    nil,
+		group:    "",
+	}, nil
+}
+
+// Enabled reports whether the record is handled by the wrapped handler or raises an alert.
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() || h.next.Enabled(ctx, level)
+}
+
+// Handle passes the record to the wrapped handler and sends the alert if the record level is high enough.
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+
+	if h.next.Enabled(ctx, r.Level) {
+		if err := h.next.Handle(ctx, r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if r.Level >= h.level.Level() {
+		if err := h.alert(ctx, r); err != nil {
+			errs = append(errs, fmt.Errorf("send alert: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// WithAttrs returns a new handler with the attributes added to both the wrapped handler and alerts.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	c := h.clone()
+	c.next = h.next.WithAttrs(attrs)
+
+	for _, a := range attrs {
+		c.attrs = appendSlogAttr(c.attrs, h.group, a)
+	}
+
+	return c
+}
+
+// WithGroup returns a new handler with the group added to both the wrapped handler and alerts.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	c := h.clone()
+	c.next = h.next.WithGroup(name)
+	c.group = joinSlogKey(h.group, name)
+
+	return c
+}
+
+func (h *slogHandler) clone() *slogHandler {
+	c := *h
+	c.attrs = append([]slog.Attr(nil), h.attrs...)
+
+	return &c
+}
+
+func (h *slogHandler) alert(ctx context.Context, r slog.Record) error {
+	attrs := append([]slog.Attr(nil), h.attrs...)
+
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = appendSlogAttr(attrs, h.group, a)
+
+		return true
+	})
+
+	md := Metadata{
+		Extra: make(map[string]string, len(attrs)),
+	}
+
+	for _, a := range attrs {
+		md.Extra[a.Key] = a.Value.String()
+	}
+
+	return h.notifier.Alert(ContextWithMetadata(ctx, md), slogSeverity(r.Level), r.Message)
+}
+
+// slogSeverity maps slog level to Severity.
+func slogSeverity(level slog.Level) Severity {
+	switch {
+	case level >= slog.LevelError+4:
+		return SeverityCritical
+	case level >= slog.LevelError:
+		return SeverityError
+	case level >= slog.LevelWarn:
+		return SeverityWarning
+	case level >= slog.LevelInfo:
+		return SeverityInfo
+	default:
+		return SeverityDebug
+	}
+}
+
+// appendSlogAttr appends the attribute with the prefixed key, flattening groups.
+func appendSlogAttr(attrs []slog.Attr, prefix string, a slog.Attr) []slog.Attr {
+	a.Value = a.Value.Resolve()
+
+	if a.Equal(slog.Attr{}) {
+		return attrs
+	}
+
+	if a.Value.Kind() != slog.KindGroup {
+		return append(attrs, slog.Attr{Key: joinSlogKey(prefix, a.Key), Value: a.Value})
+	}
+
+	// Inline groups with empty keys.
+	prefix = joinSlogKey(prefix, a.Key)
+
+	for _, ga := range a.Value.Group() {
+		attrs = appendSlogAttr(attrs, prefix, ga)
+	}
+
+	return attrs
+}
+
+func joinSlogKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	if key == "" {
+		return prefix
+	}
+
+	return strings.Join([]string{prefix, key}, ".")
+}
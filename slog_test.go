@@ -0,0 +1,83 @@
+package notifier_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/notifier"
+)
+
+func TestSlogHandler(t *testing.T) {
+	var logs, alerts bytes.Buffer
+
+	h, err := notifier.NewSlogHandler(
+		slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelInfo}),
+		newTestNotifier(t, &alerts, "slog"),
+		&notifier.SlogHandlerOptions{Level: slog.LevelWarn},
+	)
+	require.NoError(t, err)
+
+	logger := slog.New(h).With("service", "api").WithGroup("req")
+
+	ctx := notifier.ContextWithMetadata(context.Background(), notifier.Metadata{AppName: "test_app"})
+
+	logger.DebugContext(ctx, "debug message")
+	logger.InfoContext(ctx, "info message", "id", 1)
+
+	assert.NotContains(t, logs.String(), "debug message")
+	assert.Contains(t, logs.String(), "info message")
+	assert.Empty(t, alerts.String())
+
+	logger.WarnContext(ctx, "warn message", "id", 2)
+
+	assert.Contains(t, logs.String(), "warn message")
+	assert.Contains(t, alerts.String(), "WARNING\nAlert Message: warn message")
+
+	alerts.Reset()
+
+	logger.ErrorContext(ctx, "error message", "id", 3, slog.Group("user", "name", "john"))
+
+	got := alerts.String()
+
+	for _, want := range []string{
+		"ERROR\nAlert Message: error message",
+		"• app_name: test_app",
+		"• service: api",
+		"• req.id: 3",
+		"• req.user.name: john",
+	} {
+		assert.Contains(t, got, want)
+	}
+
+	alerts.Reset()
+
+	logger.Log(ctx, slog.LevelError+4, "fatal message")
+	assert.Contains(t, alerts.String(), "CRITICAL\nAlert Message: fatal message")
+}
+
+func TestSlogHandler_DefaultLevel(t *testing.T) {
+	var logs, alerts bytes.Buffer
+
+	h, err := notifier.NewSlogHandler(
+		slog.NewTextHandler(&logs, nil),
+		newTestNotifier(t, &alerts, "slog"),
+		nil,
+	)
+	require.NoError(t, err)
+
+	logger := slog.New(h)
+
+	assert.True(t, h.Enabled(context.Background(), slog.LevelInfo))
+	assert.False(t, h.Enabled(context.Background(), slog.LevelDebug))
+
+	logger.Warn("warn message")
+	assert.Empty(t, alerts.String())
+
+	logger.Error("error message")
+	assert.Contains(t, alerts.String(), "error message")
+}
@@ -0,0 +1,285 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// SMSSender declares the contract of an SMS gateway.
+type SMSSender interface {
+	// SendSMS sends a plain-text message to the given phone number.
+	SendSMS(ctx context.Context, to, body string) error
+	// Kind returns the sender kind.
+	Kind() string
+}
+
+const (
+	// defaultTwilioBaseURL is the base URL of the Twilio REST API.
+	defaultTwilioBaseURL = "https://api.twilio.com"
+	// defaultSMSMaxLength is the length of a single SMS segment in GSM-7 characters.
+	defaultSMSMaxLength = 160
+	// smsUCS2MaxLength is the length of a single SMS segment in UCS-2 characters.
+	smsUCS2MaxLength = 70
+	// gsm7Basic is the GSM 03.38 basic character set.
+	gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+		"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	// gsm7Extension is the GSM 03.38 extension table, its characters take two septets.
+	gsm7Extension = "^{}\\[~]|€\f"
+	// defaultSMSTimeout limits the time of a single SMS request.
+	defaultSMSTimeout = 10 * time.Second
+)
+
+// TwilioConfig holds the configuration of a Twilio-compatible SMS gateway.
+type TwilioConfig struct {
+	// AccountSID is the account identifier, used as basic auth username.
+	AccountSID string
+	// AuthToken is the account secret, used as basic auth password.
+	AuthToken string
+	// From is the sender phone number.
+	From string
+	// BaseURL is the API base URL. If empty, the Twilio API is used.
+	// Useful for Twilio-compatible gateways and testing.
+	BaseURL string
+	// HTTPClient is used to send requests. If nil, a client with a 10s timeout is used.
+	HTTPClient *http.Client
+}
+
+// twilioSender sends SMS using the Twilio Messages API.
+type twilioSender struct {
+	cfg    TwilioConfig
+	client *http.Client
+}
+
+// NewTwilioSender returns a new SMSSender that uses the Twilio Messages API.
+func NewTwilioSender(cfg TwilioConfig) (SMSSender, error) {
+	if cfg.AccountSID == "" {
+		return nil, ErrEmptyTwilioAccountSID
+	}
+
+	if cfg.AuthToken == "" {
+		return nil, ErrEmptyTwilioAuthToken
+	}
+
+	if cfg.From == "" {
+		return nil, ErrEmptySMSFrom
+	}
+
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaultTwilioBaseURL
+	}
+
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: defaultSMSTimeout}
+	}
+
+	return &twilioSender{
+		cfg:    cfg,
+		client: client,
+	}, nil
+}
+
+// Kind returns the sender kind.
+func (s *twilioSender) Kind() string {
+	return "twilio"
+}
+
+// SendSMS sends a message using the Twilio Messages API.
+func (s *twilioSender) SendSMS(ctx context.Context, to, body string) error {
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", s.cfg.BaseURL, url.PathEscape(s.cfg.AccountSID))
+
+	form := url.Values{}
+	form.Set("From", s.cfg.From)
+	form.Set("To", to)
+	form.Set("Body", body)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	req.SetBasicAuth(s.cfg.AccountSID, s.cfg.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", newDeliveryError(ctx, s.Kind(), 0, 0, err))
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		const maxErrBody = 512
+
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrBody))
+
+		msg := strings.TrimSpace(string(b))
+		if msg == "" {
+			msg = http.StatusText(resp.StatusCode)
+		}
+
+		return newDeliveryError(ctx, s.Kind(), resp.StatusCode,
+			parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()), errors.New(msg))
+	}
+
+	return nil
+}
+
+// smsNotifier sends critical and higher alerts as SMS.
+type smsNotifier struct {
+	sender SMSSender
+	to     []string
+	maxLen int
+}
+
+// NewSMSNotifier returns a new notifier that sends alerts as plain-text SMS to the given phone numbers.
+// SMS is a last-resort channel, so only SeverityCritical and higher alerts are sent, others are dropped silently.
+// Messages longer than a single SMS segment, 160 GSM-7 or 70 UCS-2 characters, are trimmed.
+func NewSMSNotifier(sender SMSSender, to ...string) (Notifier, error) {
+	if sender == nil {
+		return nil, fmt.Errorf("sms sender is nil")
+	}
+
+	if len(to) == 0 {
+		return nil, ErrEmptySMSRecipients
+	}
+
+	return &smsNotifier{
+		sender: sender,
+		to:     to,
+		maxLen: defaultSMSMaxLength,
+	}, nil
+}
+
+// NewTwilioSMS returns a new SMS notifier that uses the Twilio Messages API.
+func NewTwilioSMS(cfg TwilioConfig, to ...string) (Notifier, error) {
+	sender, err := NewTwilioSender(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create twilio sender: %w", err)
+	}
+
+	return NewSMSNotifier(sender, to...)
+}
+
+// Kind returns the notifier kind.
+func (s *smsNotifier) Kind() string {
+	return fmt.Sprintf("sms[%s]", s.sender.Kind())
+}
+
+// Alert sends a critical or higher alert to all recipients.
+func (s *smsNotifier) Alert(ctx context.Context, severity Severity, message string) error {
+	alert, err := formatSMS(ctx, severity, message, s.maxLen)
+	if err != nil {
+		return fmt.Errorf("format alert: %w", err)
+	}
+
+	if severity < SeverityCritical {
+		return nil
+	}
+
+	var errs []error
+
+	for _, to := range s.to {
+		if err = s.sender.SendSMS(ctx, to, alert); err != nil {
+			errs = append(errs, fmt.Errorf("send sms to '%s': %w", to, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// formatSMS renders the alert as a single line of plain text, trimmed to maxLen runes.
+func formatSMS(ctx context.Context, severity Severity, message string, maxLen int) (string, error) {
+	if err := validateAlert(severity, message); err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("[" + severity.String() + "]")
+
+	if m, ok := MetadataFromContext(ctx); ok && m.AppName != "" {
+		sb.WriteString(" " + m.AppName + ":")
+	}
+
+	sb.WriteString(" " + strings.Join(strings.Fields(message), " "))
+
+	return truncateSMS(sb.String(), maxLen), nil
+}
+
+// truncateSMS trims the text to maxLen GSM-7 characters or, if the text has characters out of
+// the GSM-7 alphabet and is sent in UCS-2, to 70 UTF-16 code units. The cut is marked with "...",
+// since the ellipsis character is not in GSM-7 and would switch the whole text to UCS-2.
+func truncateSMS(s string, maxLen int) string {
+	gsm7 := true
+
+	for _, r := range s {
+		if gsm7Length(r) == 0 {
+			gsm7 = false
+
+			break
+		}
+	}
+
+	length := gsm7Length
+	if !gsm7 {
+		length = utf16.RuneLen
+		maxLen = min(maxLen, smsUCS2MaxLength)
+	}
+
+	const ellipsis = "..."
+
+	var n, cut int
+
+	for i, r := range s {
+		n += length(r)
+
+		if n <= maxLen-len(ellipsis) {
+			cut = i + utf8.RuneLen(r)
+		}
+
+		if n > maxLen {
+			return s[:cut] + ellipsis
+		}
+	}
+
+	return s
+}
+
+// gsm7Length returns the number of GSM-7 septets of the character: 1 for the basic alphabet,
+// 2 for the extension table and 0 if the character is not in GSM-7.
+func gsm7Length(r rune) int {
+	switch {
+	case strings.ContainsRune(gsm7Basic, r):
+		return 1
+	case strings.ContainsRune(gsm7Extension, r):
+		return 2
+	default:
+		return 0
+	}
+}
+
+// truncate trims s to maxLen runes, marking the cut with an ellipsis.
+func truncate(s string, maxLen int) string {
+	if maxLen <= 0 || utf8.RuneCountInString(s) <= maxLen {
+		return s
+	}
+
+	const ellipsis = "…"
+
+	runes := []rune(s)
+
+	return string(runes[:maxLen-1]) + ellipsis
+}
@@ -0,0 +1,210 @@
+package notifier_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/notifier"
+)
+
+type smsRequest struct {
+	path     string
+	user     string
+	password string
+	from     string
+	to       string
+	body     string
+}
+
+func newTwilioServer(tb testing.TB, status int) (*httptest.Server, func() []smsRequest) {
+	tb.Helper()
+
+	var (
+		mu   sync.Mutex
+		reqs []smsRequest
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(tb, r.ParseForm())
+
+		user, password, _ := r.BasicAuth()
+
+		mu.Lock()
+		reqs = append(reqs, smsRequest{
+			path:     r.URL.Path,
+			user:     user,
+			password: password,
+			from:     r.PostForm.Get("From"),
+			to:       r.PostForm.Get("To"),
+			body:     r.PostForm.Get("Body"),
+		})
+		mu.Unlock()
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"message": "test"}`))
+	}))
+
+	tb.Cleanup(srv.Close)
+
+	return srv, func() []smsRequest {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return reqs
+	}
+}
+
+func TestTwilioSMS_Alert(t *testing.T) {
+	srv, requests := newTwilioServer(t, http.StatusCreated)
+
+	n, err := notifier.NewTwilioSMS(notifier.TwilioConfig{
+		AccountSID: "AC123",
+		AuthToken:  "secret",
+		From:       "+10000000000",
+		BaseURL:    srv.URL,
+	}, "+10000000001", "+10000000002")
+	require.NoError(t, err)
+
+	assert.Equal(t, "sms[twilio]", n.Kind())
+
+	ctx := notifier.ContextWithMetadata(context.Background(), notifier.Metadata{
+		AppName: "test_app",
+	})
+
+	require.NoError(t, n.Alert(ctx, notifier.SeverityWarning, "not critical"))
+	assert.Empty(t, requests(), "non critical alerts should be dropped")
+
+	require.NoError(t, n.Alert(ctx, notifier.SeverityCritical, strings.Repeat("database is down\n", 20)))
+
+	reqs := requests()
+	require.Len(t, reqs, 2)
+
+	for i, to := range []string{"+10000000001", "+10000000002"} {
+		req := reqs[i]
+
+		assert.Equal(t, "/2010-04-01/Accounts/AC123/Messages.json", req.path)
+		assert.Equal(t, "AC123", req.user)
+		assert.Equal(t, "secret", req.password)
+		assert.Equal(t, "+10000000000", req.from)
+		assert.Equal(t, to, req.to)
+		assert.True(t, strings.HasPrefix(req.body, "[CRITICAL] test_app: database is down database is down"))
+		assert.True(t, strings.HasSuffix(req.body, "..."))
+		// 160 septets, the brackets of the severity take two septets each.
+		assert.Len(t, req.body, 158)
+	}
+
+	// Text out of GSM-7 is sent in UCS-2 with 70 characters per segment.
+	require.NoError(t, n.Alert(ctx, notifier.SeverityCritical, strings.Repeat("база недоступна\n", 10)))
+
+	reqs = requests()
+	require.Len(t, reqs, 4)
+	assert.True(t, strings.HasPrefix(reqs[3].body, "[CRITICAL] test_app: база недоступна"))
+	assert.True(t, strings.HasSuffix(reqs[3].body, "..."))
+	assert.Len(t, []rune(reqs[3].body), 70)
+
+	require.ErrorIs(t, n.Alert(ctx, notifier.SeverityCritical, ""), notifier.ErrEmptyMessage)
+}
+
+func TestTwilioSMS_AlertFailed(t *testing.T) {
+	srv, _ := newTwilioServer(t, http.StatusUnauthorized)
+
+	n, err := notifier.NewTwilioSMS(notifier.TwilioConfig{
+		AccountSID: "AC123",
+		AuthToken:  "secret",
+		From:       "+10000000000",
+		BaseURL:    srv.URL,
+	}, "+10000000001")
+	require.NoError(t, err)
+
+	err = n.Alert(context.Background(), notifier.SeverityCritical, "test")
+	require.EqualError(t, err, `send sms to '+10000000001': unexpected status 401: {"message": "test"}`)
+	require.ErrorIs(t, err, notifier.ErrUnauthorized)
+	assert.False(t, notifier.IsRetryable(err))
+
+	var derr *notifier.DeliveryError
+
+	require.ErrorAs(t, err, &derr)
+	assert.Equal(t, "twilio", derr.Kind)
+	assert.Equal(t, http.StatusUnauthorized, derr.StatusCode)
+}
+
+func TestTwilioSMS_AlertRateLimited(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(srv.Close)
+
+	n, err := notifier.NewTwilioSMS(notifier.TwilioConfig{
+		AccountSID: "AC123",
+		AuthToken:  "secret",
+		From:       "+10000000000",
+		BaseURL:    srv.URL,
+	}, "+10000000001")
+	require.NoError(t, err)
+
+	err = n.Alert(context.Background(), notifier.SeverityCritical, "test")
+	require.EqualError(t, err, `send sms to '+10000000001': unexpected status 429: Too Many Requests`)
+	require.ErrorIs(t, err, notifier.ErrRateLimited)
+	assert.True(t, notifier.IsRetryable(err))
+
+	var derr *notifier.DeliveryError
+
+	require.ErrorAs(t, err, &derr)
+	assert.Equal(t, 7*time.Second, derr.RetryAfter)
+}
+
+func TestNewTwilioSMS(t *testing.T) {
+	valid := notifier.TwilioConfig{
+		AccountSID: "AC123",
+		AuthToken:  "secret",
+		From:       "+10000000000",
+	}
+
+	tests := []struct {
+		name    string
+		cfg     func() notifier.TwilioConfig
+		to      []string
+		wantErr error
+	}{
+		{
+			name:    "empty account sid",
+			cfg:     func() notifier.TwilioConfig { c := valid; c.AccountSID = ""; return c },
+			to:      []string{"+1"},
+			wantErr: notifier.ErrEmptyTwilioAccountSID,
+		},
+		{
+			name:    "empty auth token",
+			cfg:     func() notifier.TwilioConfig { c := valid; c.AuthToken = ""; return c },
+			to:      []string{"+1"},
+			wantErr: notifier.ErrEmptyTwilioAuthToken,
+		},
+		{
+			name:    "empty from",
+			cfg:     func() notifier.TwilioConfig { c := valid; c.From = ""; return c },
+			to:      []string{"+1"},
+			wantErr: notifier.ErrEmptySMSFrom,
+		},
+		{
+			name:    "empty recipients",
+			cfg:     func() notifier.TwilioConfig { return valid },
+			to:      nil,
+			wantErr: notifier.ErrEmptySMSRecipients,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := notifier.NewTwilioSMS(tt.cfg(), tt.to...)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
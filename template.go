@@ -0,0 +1,202 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"sync"
+	"text/template"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TemplateFuncs returns the functions available in alert templates:
+//
+//	severityEmoji .Severity              emoji of the severity
+//	formatTime "2006-01-02" .Time        time formatted with the layout
+//	truncate 100 .Message                string trimmed to the number of characters, HTML entities are not cut
+//	sortedMetadata .Metadata             metadata as a list of MetadataField sorted by key
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"severityEmoji":  severityEmoji,
+		"formatTime":     formatTime,
+		"truncate":       templateTruncate,
+		"sortedMetadata": sortedMetadata,
+	}
+}
+
+func formatTime(layout string, t time.Time) string {
+	return t.Format(layout)
+}
+
+// templateTruncate trims the HTML escaped template value, counting the characters of the unescaped text.
+func templateTruncate(maxLen int, s string) string {
+	return tgbotapi.EscapeText(tgbotapi.ModeHTML, truncate(html.UnescapeString(s), maxLen))
+}
+
+// Templates is a set of alert templates in Golang text/template format, selectable per Severity.
+// Templates are executed with AlertData and have access to TemplateFuncs.
+// Templates implement Formatter producing Telegram HTML: the message and metadata are HTML escaped.
+// The zero value has no default template, so only the severities set with SetSeverityTemplate are formatted.
+type Templates struct {
+	funcs template.FuncMap
+
+	mu         sync.RWMutex
+	def        *template.Template
+	bySeverity map[Severity]*template.Template
+}
+
+// NewTemplates returns a new template set with the default template.
+// Optional funcs are added to TemplateFuncs and could override them.
+func NewTemplates(text string, funcs ...template.FuncMap) (*Templates, error) {
+	fm := TemplateFuncs()
+
+	for _, f := range funcs {
+		for k, v := range f {
+			fm[k] = v
+		}
+	}
+
+	t := &Templates{
+		funcs:      fm,
+		bySeverity: make(map[Severity]*template.Template),
+	}
+
+	def, err := t.parse("alert", text)
+	if err != nil {
+		return nil, err
+	}
+
+	t.def = def
+
+	return t, nil
+}
+
+// DefaultTemplates returns a copy of the template set used by notifiers by default.
+// Changing the copy does not affect other notifiers.
+func DefaultTemplates() *Templates {
+	return defaultTemplates.Clone()
+}
+
+// Clone returns a copy of the template set, which could be changed independently.
+func (t *Templates) Clone() *Templates {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	c := &Templates{
+		funcs:      t.funcs,
+		def:        t.def,
+		bySeverity: make(map[Severity]*template.Template, len(t.bySeverity)),
+	}
+
+	// Parsed templates are not changed after parsing, so they could be shared.
+	for s, tpl := range t.bySeverity {
+		c.bySeverity[s] = tpl
+	}
+
+	return c
+}
+
+// SetSeverityTemplate sets the template for alerts of the given severity.
+func (t *Templates) SetSeverityTemplate(severity Severity, text string) error {
+	if !severity.Valid() {
+		return fmt.Errorf("'%s', should be one of '%v': %w", severity, allowedSeverities(), ErrInvalidSeverity)
+	}
+
+	tpl, err := t.parse("alert_"+severity.String(), text)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.bySeverity == nil {
+		t.bySeverity = make(map[Severity]*template.Template)
+	}
+
+	t.bySeverity[severity] = tpl
+
+	return nil
+}
+
+func (t *Templates) parse(name, text string) (*template.Template, error) {
+	funcs := t.funcs
+	if funcs == nil {
+		funcs = TemplateFuncs()
+	}
+
+	tpl, err := template.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse template '%s': %w", name, err)
+	}
+
+	return tpl, nil
+}
+
+func (t *Templates) template(severity Severity) *template.Template {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if tpl, ok := t.bySeverity[severity]; ok {
+		return tpl
+	}
+
+	return t.def
+}
+
+// ParseMode returns the Telegram parse mode of the formatted message.
+func (t *Templates) ParseMode() string {
+	return tgbotapi.ModeHTML
+}
+
+// Format renders the alert with the template selected by severity.
+func (t *Templates) Format(ctx context.Context, severity Severity, message string) (string, error) {
+	if err := validateAlert(severity, message); err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+
+	ad := AlertData{
+		Message:  tgbotapi.EscapeText(tgbotapi.ModeHTML, message),
+		Severity: severity,
+		Metadata: nil,
+		Time:     time.Now(),
+	}
+
+	m, ok := MetadataFromContext(ctx)
+	if ok {
+		ad.Fields = escapeHTMLFields(m.fields())
+		ad.Metadata = fieldsMap(ad.Fields)
+	}
+
+	tpl := t.template(severity)
+	if tpl == nil {
+		return "", fmt.Errorf("'%s' alert: %w", severity, ErrNoTemplate)
+	}
+
+	if err := tpl.Execute(&buf, ad); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// escapeHTMLFields escapes the metadata fields for Telegram HTML.
+func escapeHTMLFields(fields []MetadataField) []MetadataField {
+	escaped := make([]MetadataField, 0, len(fields))
+
+	for _, f := range fields {
+		escaped = append(escaped, MetadataField{
+			Key:   tgbotapi.EscapeText(tgbotapi.ModeHTML, f.Key),
+			Value: tgbotapi.EscapeText(tgbotapi.ModeHTML, f.Value),
+			Label: tgbotapi.EscapeText(tgbotapi.ModeHTML, f.Label),
+			Group: tgbotapi.EscapeText(tgbotapi.ModeHTML, f.Group),
+		})
+	}
+
+	return escaped
+}
@@ -0,0 +1,108 @@
+package notifier_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/notifier"
+)
+
+func TestTemplates(t *testing.T) {
+	tpl, err := notifier.NewTemplates(
+		`{{severityEmoji .Severity}} {{.Severity}} {{truncate 10 .Message}} at {{formatTime "2006" .Time}}` +
+			`{{range sortedMetadata .Metadata}} {{.Key}}={{.Value}}{{end}}`,
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, tpl.SetSeverityTemplate(notifier.SeverityCritical, `{{.Message}}!`))
+
+	require.ErrorIs(t, tpl.SetSeverityTemplate(notifier.Severity(100), "{{.Message}}"), notifier.ErrInvalidSeverity)
+
+	var buf bytes.Buffer
+
+	n, err := notifier.NewWriterNotifier(&buf, notifier.WithKind("custom"), notifier.WithTemplates(tpl))
+	require.NoError(t, err)
+
+	assert.Equal(t, "iowriter: custom", n.Kind())
+
+	ctx := notifier.ContextWithMetadata(context.Background(), notifier.Metadata{
+		AppName: "test_app",
+		Commit:  "test_commit",
+	})
+
+	require.NoError(t, n.Alert(ctx, notifier.SeverityInfo, "a long message <b>"))
+
+	got := buf.String()
+	assert.True(t, strings.HasPrefix(got, "ℹ️ INFO a long me… at 20"), got)
+	assert.True(t, strings.HasSuffix(got, " app_name=test_app commit=test_commit\n"), got)
+
+	buf.Reset()
+
+	require.NoError(t, n.Alert(ctx, notifier.SeverityCritical, "critical"))
+	assert.Equal(t, "critical!\n", buf.String())
+
+	buf.Reset()
+
+	_, err = notifier.NewTemplates("{{upper .Message}}")
+	require.Error(t, err, "unknown function")
+
+	withFuncs, err := notifier.NewTemplates("{{upper .Message}}", template.FuncMap{"upper": strings.ToUpper})
+	require.NoError(t, err)
+
+	n, err = notifier.NewWriterNotifier(&buf, notifier.WithTemplates(withFuncs))
+	require.NoError(t, err)
+
+	require.NoError(t, n.Alert(ctx, notifier.SeverityWarning, "custom funcs"))
+	assert.Equal(t, "CUSTOM FUNCS\n", buf.String())
+
+	require.ErrorIs(t, n.Alert(ctx, notifier.SeverityWarning, ""), notifier.ErrEmptyMessage)
+}
+
+func TestDefaultTemplates(t *testing.T) {
+	got, err := notifier.DefaultTemplates().Format(context.Background(), notifier.SeverityWarning, "message")
+	require.NoError(t, err)
+
+	assert.Equal(t, "<b>⚠️ Severity:</b> WARNING\n<b>Alert Message:</b> message", got)
+
+	// Changing the returned templates does not change the defaults.
+	require.NoError(t, notifier.DefaultTemplates().SetSeverityTemplate(notifier.SeverityWarning, "changed"))
+
+	got, err = notifier.DefaultTemplates().Format(context.Background(), notifier.SeverityWarning, "message")
+	require.NoError(t, err)
+
+	assert.Equal(t, "<b>⚠️ Severity:</b> WARNING\n<b>Alert Message:</b> message", got)
+}
+
+func TestTemplates_TruncateEscaped(t *testing.T) {
+	tpl, err := notifier.NewTemplates(`{{truncate 4 .Message}}`)
+	require.NoError(t, err)
+
+	got, err := tpl.Format(context.Background(), notifier.SeverityInfo, "a<b>c")
+	require.NoError(t, err)
+
+	assert.Equal(t, "a&lt;b…", got)
+}
+
+func TestTemplates_ZeroValue(t *testing.T) {
+	var tpl notifier.Templates
+
+	ctx := context.Background()
+
+	_, err := tpl.Format(ctx, notifier.SeverityInfo, "message")
+	require.ErrorIs(t, err, notifier.ErrNoTemplate)
+
+	require.NoError(t, tpl.SetSeverityTemplate(notifier.SeverityCritical, `{{truncate 4 .Message}}`))
+
+	got, err := tpl.Format(ctx, notifier.SeverityCritical, "message")
+	require.NoError(t, err)
+	assert.Equal(t, "mes…", got)
+
+	_, err = tpl.Format(ctx, notifier.SeverityInfo, "message")
+	require.ErrorIs(t, err, notifier.ErrNoTemplate)
+}